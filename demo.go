@@ -12,10 +12,13 @@ import (
 
 func main() {
 	if true {
-		// vl.SpecificSymbol(false)
 		action := make(chan func(), 10)
-		root := vl.Demo()[0]
-		err := vl.Run(root, action, nil, tcell.KeyCtrlC)
+		var root vl.Screen
+		root.SetRoot(vl.Demo()[0])
+		// theme := vl.LightTheme()
+		// theme.SpecificSymbol(false)
+		// root.SetTheme(theme)
+		err := vl.Run(&root, action, nil, tcell.KeyCtrlC)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "%v", err)
 			os.Exit(1)
@@ -2,13 +2,23 @@
 package vl
 
 import (
+	"encoding/base64"
 	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/Konstantin8105/tf"
 	"github.com/gdamore/tcell/v2"
@@ -26,7 +36,204 @@ var (
 	red    = tcell.ColorRed
 	green  = tcell.ColorGreen
 	black  = tcell.ColorBlack
+)
+
+// Theme is styles and symbols for drawing widgets.
+// Theme is attached to Screen by function SetTheme or to part of
+// widgets tree by widget Themed.
+type Theme struct {
+	ScreenStyle        tcell.Style
+	TextStyle          tcell.Style
+	ButtonStyle        tcell.Style
+	ButtonFocusStyle   tcell.Style
+	ButtonSelectStyle  tcell.Style
+	InputBoxStyle      tcell.Style
+	InputBoxFocusStyle tcell.Style
+	// cursor
+	CursorStyle tcell.Style
+	Cursor      rune
+	// select
+	InputBoxSelectStyle tcell.Style
+	TableHeaderStyle    tcell.Style
+	SearchStyle         tcell.Style
+	// markdown
+	HeadingStyle   tcell.Style
+	CodeStyle      tcell.Style
+	QuoteStyle     tcell.Style
+	LinkStyle      tcell.Style
+	LinkFocusStyle tcell.Style
+
+	// specific symbols for borders
+	LineHorizontalFocus    rune
+	LineHorizontalUnfocus  rune
+	LineVerticalFocus      rune
+	LineVerticalUnfocus    rune
+	CornerLeftUpFocus      rune
+	CornerLeftDownFocus    rune
+	CornerRightUpFocus     rune
+	CornerRightDownFocus   rune
+	CornerLeftUpUnfocus    rune
+	CornerLeftDownUnfocus  rune
+	CornerRightUpUnfocus   rune
+	CornerRightDownUnfocus rune
+	ScrollLine             rune
+	ScrollUp               rune
+	ScrollDown             rune
+	ScrollSquare           rune
+	TreeUpDown             rune
+	TreeUp                 rune
+	TableCross             rune
+	TableTruncate          rune
+	SpinUp                 rune
+	SpinDown               rune
+	WrapMarker             rune
+	Bullet                 rune
+}
+
+// LightTheme return default theme with black text on white screen.
+// Theme use values of deprecated style and symbol variables.
+func LightTheme() *Theme {
+	t := &Theme{
+		ScreenStyle:         ScreenStyle,
+		TextStyle:           TextStyle,
+		ButtonStyle:         ButtonStyle,
+		ButtonFocusStyle:    ButtonFocusStyle,
+		ButtonSelectStyle:   ButtonSelectStyle,
+		InputBoxStyle:       InputBoxStyle,
+		InputBoxFocusStyle:  InputBoxFocusStyle,
+		CursorStyle:         CursorStyle,
+		InputBoxSelectStyle: InputBoxSelectStyle,
+		TableHeaderStyle:    Style(black, yellow),
+		SearchStyle:         Style(black, tcell.ColorAqua),
+		HeadingStyle:        Style(black, white).Bold(true),
+		CodeStyle:           Style(black, tcell.ColorSilver),
+		QuoteStyle:          Style(tcell.ColorGray, white),
+		LinkStyle:           Style(tcell.ColorBlue, white).Underline(true),
+		LinkFocusStyle:      Style(black, focus).Underline(true),
+	}
+	t.SpecificSymbol(symbolsASCII)
+	t.Cursor = Cursor
+	for _, v := range []struct {
+		r      *rune
+		symbol rune
+	}{
+		{&t.LineHorizontalFocus, LineHorizontalFocus},
+		{&t.LineHorizontalUnfocus, LineHorizontalUnfocus},
+		{&t.LineVerticalFocus, LineVerticalFocus},
+		{&t.LineVerticalUnfocus, LineVerticalUnfocus},
+		{&t.CornerLeftUpFocus, CornerLeftUpFocus},
+		{&t.CornerLeftDownFocus, CornerLeftDownFocus},
+		{&t.CornerRightUpFocus, CornerRightUpFocus},
+		{&t.CornerRightDownFocus, CornerRightDownFocus},
+		{&t.CornerLeftUpUnfocus, CornerLeftUpUnfocus},
+		{&t.CornerLeftDownUnfocus, CornerLeftDownUnfocus},
+		{&t.CornerRightUpUnfocus, CornerRightUpUnfocus},
+		{&t.CornerRightDownUnfocus, CornerRightDownUnfocus},
+		{&t.ScrollLine, ScrollLine},
+		{&t.ScrollUp, ScrollUp},
+		{&t.ScrollDown, ScrollDown},
+		{&t.ScrollSquare, ScrollSquare},
+		{&t.TreeUpDown, TreeUpDown},
+		{&t.TreeUp, TreeUp},
+	} {
+		*v.r = v.symbol
+	}
+	return t
+}
+
+// DarkTheme return theme with white text on black screen
+func DarkTheme() *Theme {
+	navy := tcell.ColorNavy
+	t := &Theme{
+		ScreenStyle:         Style(white, black),
+		TextStyle:           Style(white, black),
+		ButtonStyle:         Style(white, navy),
+		ButtonFocusStyle:    Style(black, focus),
+		ButtonSelectStyle:   Style(black, green),
+		InputBoxStyle:       Style(white, navy),
+		InputBoxFocusStyle:  Style(black, focus),
+		CursorStyle:         Style(white, red),
+		InputBoxSelectStyle: Style(black, green),
+		TableHeaderStyle:    Style(white, navy),
+		SearchStyle:         Style(black, tcell.ColorAqua),
+		HeadingStyle:        Style(white, black).Bold(true),
+		CodeStyle:           Style(white, tcell.ColorGray),
+		QuoteStyle:          Style(tcell.ColorSilver, black),
+		LinkStyle:           Style(tcell.ColorAqua, black).Underline(true),
+		LinkFocusStyle:      Style(black, focus).Underline(true),
+	}
+	t.SpecificSymbol(true)
+	return t
+}
+
+// HighContrastTheme return theme with maximal contrast of colors
+func HighContrastTheme() *Theme {
+	t := &Theme{
+		ScreenStyle:         Style(white, black),
+		TextStyle:           Style(white, black),
+		ButtonStyle:         Style(black, white),
+		ButtonFocusStyle:    Style(black, yellow).Bold(true),
+		ButtonSelectStyle:   Style(black, tcell.ColorLime),
+		InputBoxStyle:       Style(black, white),
+		InputBoxFocusStyle:  Style(black, yellow).Bold(true),
+		CursorStyle:         Style(black, tcell.ColorAqua),
+		InputBoxSelectStyle: Style(black, tcell.ColorLime),
+		TableHeaderStyle:    Style(black, white).Bold(true),
+		SearchStyle:         Style(black, tcell.ColorFuchsia),
+		HeadingStyle:        Style(white, black).Bold(true),
+		CodeStyle:           Style(black, white),
+		QuoteStyle:          Style(white, black).Italic(true),
+		LinkStyle:           Style(tcell.ColorYellow, black).Underline(true),
+		LinkFocusStyle:      Style(black, yellow).Underline(true).Bold(true),
+	}
+	t.SpecificSymbol(true)
+	return t
+}
 
+// SpecificSymbol set ascii or unicode symbols for borders
+func (t *Theme) SpecificSymbol(ascii bool) {
+	t.Cursor = '_'
+	for _, v := range []struct {
+		r       *rune
+		acsii   rune
+		unicode rune
+	}{
+		{&t.LineHorizontalFocus, '=', '\u2550'},
+		{&t.LineHorizontalUnfocus, '-', '\u2500'},
+		{&t.LineVerticalFocus, 'I', '\u2551'},
+		{&t.LineVerticalUnfocus, '|', '\u2502'},
+		{&t.CornerLeftUpFocus, '+', '\u2554'},
+		{&t.CornerLeftDownFocus, '+', '\u255A'},
+		{&t.CornerRightUpFocus, '+', '\u2557'},
+		{&t.CornerRightDownFocus, '+', '\u255D'},
+		{&t.CornerLeftUpUnfocus, '+', '\u250C'},
+		{&t.CornerLeftDownUnfocus, '+', '\u2514'},
+		{&t.CornerRightUpUnfocus, '+', '\u2510'},
+		{&t.CornerRightDownUnfocus, '+', '\u2518'},
+		{&t.ScrollLine, '|', '\u2506'},
+		{&t.ScrollUp, '-', '\u252C'},
+		{&t.ScrollDown, '-', '\u2534'},
+		{&t.ScrollSquare, '*', '\u25A0'},
+		{&t.TreeUpDown, '+', '\u251D'},
+		{&t.TreeUp, '+', '\u2514'},
+		{&t.TableCross, '+', '\u253C'},
+		{&t.TableTruncate, '~', '\u2026'},
+		{&t.SpinUp, '+', '\u25B2'},
+		{&t.SpinDown, '-', '\u25BC'},
+		{&t.WrapMarker, '\\', '\u21B5'},
+		{&t.Bullet, '*', '\u2022'},
+	} {
+		if ascii {
+			*v.r = v.acsii
+			continue
+		}
+		*v.r = v.unicode
+	}
+}
+
+// Deprecated: styles are parts of Theme, use Screen.SetTheme.
+// Values are used by LightTheme.
+var (
 	ScreenStyle        tcell.Style = Style(black, white)
 	TextStyle          tcell.Style = ScreenStyle
 	ButtonStyle        tcell.Style = Style(black, yellow)
@@ -40,10 +247,12 @@ var (
 	InputBoxSelectStyle tcell.Style = Style(black, green)
 )
 
-///////////////////////////////////////////////////////////////////////////////
+// Deprecated: cursor is part of Theme, use Screen.SetTheme.
+// Value is used by LightTheme.
+var Cursor rune = '_'
 
-// specific symbols for borders
-// default symbol '-' if not initialized symbols
+// Deprecated: symbols are parts of Theme, use Screen.SetTheme and
+// Theme.SpecificSymbol. Values are used by LightTheme.
 var (
 	LineHorizontalFocus    rune = '-'
 	LineHorizontalUnfocus       = '-'
@@ -65,40 +274,97 @@ var (
 	TreeUp                      = '-'
 )
 
+// symbolsASCII is kind of symbols of LightTheme
+var symbolsASCII = true
+
 func init() {
 	SpecificSymbol(true)
 }
 
+// Deprecated: use Theme.SpecificSymbol.
+// SpecificSymbol set ascii or unicode symbols of LightTheme.
 func SpecificSymbol(ascii bool) {
+	symbolsASCII = ascii
+	var t Theme
+	t.SpecificSymbol(ascii)
 	for _, v := range []struct {
-		r       *rune
-		acsii   rune
-		unicode rune
+		r      *rune
+		symbol rune
 	}{
-		{&LineHorizontalFocus, '=', '\u2550'},
-		{&LineHorizontalUnfocus, '-', '\u2500'},
-		{&LineVerticalFocus, 'I', '\u2551'},
-		{&LineVerticalUnfocus, '|', '\u2502'},
-		{&CornerLeftUpFocus, '+', '\u2554'},
-		{&CornerLeftDownFocus, '+', '\u255A'},
-		{&CornerRightUpFocus, '+', '\u2557'},
-		{&CornerRightDownFocus, '+', '\u255D'},
-		{&CornerLeftUpUnfocus, '+', '\u250C'},
-		{&CornerLeftDownUnfocus, '+', '\u2514'},
-		{&CornerRightUpUnfocus, '+', '\u2510'},
-		{&CornerRightDownUnfocus, '+', '\u2518'},
-		{&ScrollLine, '|', '\u2506'},
-		{&ScrollUp, '-', '\u252C'},
-		{&ScrollDown, '-', '\u2534'},
-		{&ScrollSquare, '*', '\u25A0'},
-		{&TreeUpDown, '+', '\u251D'},
-		{&TreeUp, '+', '\u2514'},
+		{&LineHorizontalFocus, t.LineHorizontalFocus},
+		{&LineHorizontalUnfocus, t.LineHorizontalUnfocus},
+		{&LineVerticalFocus, t.LineVerticalFocus},
+		{&LineVerticalUnfocus, t.LineVerticalUnfocus},
+		{&CornerLeftUpFocus, t.CornerLeftUpFocus},
+		{&CornerLeftDownFocus, t.CornerLeftDownFocus},
+		{&CornerRightUpFocus, t.CornerRightUpFocus},
+		{&CornerRightDownFocus, t.CornerRightDownFocus},
+		{&CornerLeftUpUnfocus, t.CornerLeftUpUnfocus},
+		{&CornerLeftDownUnfocus, t.CornerLeftDownUnfocus},
+		{&CornerRightUpUnfocus, t.CornerRightUpUnfocus},
+		{&CornerRightDownUnfocus, t.CornerRightDownUnfocus},
+		{&ScrollLine, t.ScrollLine},
+		{&ScrollUp, t.ScrollUp},
+		{&ScrollDown, t.ScrollDown},
+		{&ScrollSquare, t.ScrollSquare},
+		{&TreeUpDown, t.TreeUpDown},
+		{&TreeUp, t.TreeUp},
 	} {
-		if ascii {
-			*v.r = v.acsii
-			continue
-		}
-		*v.r = v.unicode
+		*v.r = v.symbol
+	}
+}
+
+// scope is environment of widget rendering
+type scope struct {
+	theme  *Theme
+	screen *Screen // screen of widget or nil
+}
+
+// scoped is part of widget for storing of rendering environment.
+// Environment is passed from parent to internal widgets at rendering.
+type scoped struct {
+	sc    *scope
+	owner atomic.Pointer[Screen] // screen of widget for MarkDirty
+}
+
+func (s *scoped) setScope(sc *scope) {
+	s.sc = sc
+	if sc != nil && sc.screen != nil {
+		s.owner.Store(sc.screen)
+	}
+}
+
+// theme return theme for rendering of widget.
+// Widget outside of screen is drawn with default theme.
+func (s *scoped) theme() *Theme {
+	if s.sc == nil {
+		s.sc = new(scope)
+	}
+	if s.sc.theme == nil {
+		s.sc.theme = LightTheme()
+	}
+	return s.sc.theme
+}
+
+// screen return screen of widget or nil
+func (s *scoped) screen() *Screen {
+	if s.sc == nil {
+		return nil
+	}
+	return s.sc.screen
+}
+
+// pass set environment of widget to internal widget `w` and return `w`
+func (s *scoped) pass(w Widget) Widget {
+	s.theme() // prepare environment
+	setScope(w, s.sc)
+	return w
+}
+
+// setScope set environment of widget `w`
+func setScope(w Widget, sc *scope) {
+	if c, ok := w.(interface{ setScope(*scope) }); ok {
+		c.setScope(sc)
 	}
 }
 
@@ -193,6 +459,69 @@ type WidgetVerticalFix interface {
 	VerticalFix
 }
 
+// Focusable is widget which may take keyboard focus
+type Focusable interface {
+	Widget
+	// AcceptFocus return true if widget may be focused by keyboard
+	AcceptFocus() bool
+	// IsFocused return focus-state of widget
+	IsFocused() bool
+}
+
+// Parent is widget with internal widgets
+type Parent interface {
+	// Children return internal widgets in focus order
+	Children() []Widget
+}
+
+// isFocused return focus-state of widget.
+// Widgets without focus-state are always focused for backward
+// compatibility, so events are still send to them.
+func isFocused(w Widget) bool {
+	if w == nil {
+		return false
+	}
+	if f, ok := w.(interface{ IsFocused() bool }); ok {
+		return f.IsFocused()
+	}
+	return true
+}
+
+// walk call function `f` for widget `w` and all internal widgets
+func walk(w Widget, f func(w Widget)) {
+	if w == nil {
+		return
+	}
+	f(w)
+	if p, ok := w.(Parent); ok {
+		for _, c := range p.Children() {
+			walk(c, f)
+		}
+	}
+}
+
+// focusPaths return all paths from widget `w` to focusable leafs
+// in order of keyboard traversal
+func focusPaths(w Widget) (paths [][]Widget) {
+	var walk func(w Widget, path []Widget)
+	walk = func(w Widget, path []Widget) {
+		if w == nil {
+			return
+		}
+		path = append(path, w)
+		if f, ok := w.(Focusable); ok && f.AcceptFocus() {
+			paths = append(paths, append([]Widget{}, path...))
+		}
+		if p, ok := w.(Parent); ok {
+			for _, c := range p.Children() {
+				walk(c, path)
+			}
+		}
+	}
+	walk(w, nil)
+	return
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 
 // Cell store internal properties of each cell
@@ -205,17 +534,59 @@ type Cell struct {
 type Screen struct {
 	ContainerVerticalFix
 	rootable
-	fill func(rune, tcell.Style)
-	//	dialog struct {
-	//		Root             Widget
-	//		offsetX, offsetY uint
-	//	}
+	fill    func(rune, tcell.Style)
+	focused Widget        // focused leaf widget
+	dialogs []*dialog     // modal dialogs, last is active
+	popup   *ContextMenu  // opened context menu
+	theme   *Theme        // theme of all widgets
+	capture *mouseCapture // capture of button press sent to widgets now
+	drag    *mouseCapture
+	paste   *strings.Builder              // pasted text between start and end of paste
+	wake    atomic.Pointer[chan struct{}] // wakeup of application at MarkDirty
+}
+
+// dialog is modal window above root widget of screen
+type dialog struct {
+	frame   Frame
+	offset  Offset
+	focused Widget // focused leaf widget before dialog opening
+	width   uint   // maximal width of dialog window
 }
 
+// dialogWidth is default maximal width of dialog window
+const dialogWidth uint = 60
+
 func (screen *Screen) Fill(fill func(rune, tcell.Style)) {
 	screen.fill = fill
 }
 
+// MarkDirty mark screen as changed and wake up application for
+// redrawing. Function is safe for use from other goroutines.
+func (screen *Screen) MarkDirty() {
+	screen.dirty.Store(true)
+	if wake := screen.wake.Load(); wake != nil {
+		select {
+		case *wake <- struct{}{}:
+		default:
+			// application is woken up already
+		}
+	}
+}
+
+// SetTheme set theme of all widgets on screen.
+// If theme is nil, then default theme is used.
+func (screen *Screen) SetTheme(t *Theme) {
+	screen.theme = t
+}
+
+// GetTheme return theme of screen
+func (screen *Screen) GetTheme() *Theme {
+	if screen.theme == nil {
+		screen.theme = LightTheme()
+	}
+	return screen.theme
+}
+
 func (screen *Screen) GetContents(width uint, cells *[][]Cell) {
 	screen.width = width
 	// zero width
@@ -279,6 +650,416 @@ func Convert(cells [][]Cell) string {
 	return buf.String()
 }
 
+// SnapshotVersion is version of snapshot format
+const SnapshotVersion = 1
+
+// snapshotKeys are keys of styles in snapshot
+const snapshotKeys = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// snapshotKey return key of style with index i
+func snapshotKey(i int) rune {
+	if i < len(snapshotKeys) {
+		return rune(snapshotKeys[i])
+	}
+	return rune(0x100 + i - len(snapshotKeys))
+}
+
+var snapshotAttrs = []struct {
+	name string
+	attr tcell.AttrMask
+}{
+	{"bold", tcell.AttrBold},
+	{"blink", tcell.AttrBlink},
+	{"reverse", tcell.AttrReverse},
+	{"underline", tcell.AttrUnderline},
+	{"dim", tcell.AttrDim},
+	{"italic", tcell.AttrItalic},
+	{"strikethrough", tcell.AttrStrikeThrough},
+}
+
+var (
+	colorNamesOnce sync.Once
+	colorNames     map[tcell.Color]string
+)
+
+// colorString return name of color
+func colorString(c tcell.Color) string {
+	colorNamesOnce.Do(func() {
+		colorNames = map[tcell.Color]string{}
+		for name, c := range tcell.ColorNames {
+			if n, ok := colorNames[c]; !ok || name < n {
+				colorNames[c] = name
+			}
+		}
+	})
+	switch {
+	case c == tcell.ColorDefault:
+		return "default"
+	case c.IsRGB():
+		return fmt.Sprintf("#%06x", c.Hex())
+	}
+	if name, ok := colorNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("color%d", c-tcell.ColorValid)
+}
+
+// parseColor return color by name from colorString
+func parseColor(name string) (tcell.Color, error) {
+	if name == "default" {
+		return tcell.ColorDefault, nil
+	}
+	if strings.HasPrefix(name, "color") {
+		if index, err := strconv.Atoi(name[5:]); err == nil && 0 <= index {
+			return tcell.PaletteColor(index), nil
+		}
+	}
+	if c := tcell.GetColor(name); c != tcell.ColorDefault {
+		return c, nil
+	}
+	return tcell.ColorDefault, fmt.Errorf("unknown color %q", name)
+}
+
+// styleString return text view of style, for example:
+// "fg:black bg:white bold underline"
+func styleString(s tcell.Style) string {
+	fg, bg, attr := s.Decompose()
+	str := "fg:" + colorString(fg) + " bg:" + colorString(bg)
+	for _, a := range snapshotAttrs {
+		if attr&a.attr != 0 {
+			str += " " + a.name
+		}
+	}
+	return str
+}
+
+// parseStyle return style from styleString
+func parseStyle(str string) (s tcell.Style, err error) {
+	s = tcell.StyleDefault
+	for _, field := range strings.Fields(str) {
+		if name, ok := strings.CutPrefix(field, "fg:"); ok {
+			c, err := parseColor(name)
+			if err != nil {
+				return s, err
+			}
+			s = s.Foreground(c)
+			continue
+		}
+		if name, ok := strings.CutPrefix(field, "bg:"); ok {
+			c, err := parseColor(name)
+			if err != nil {
+				return s, err
+			}
+			s = s.Background(c)
+			continue
+		}
+		found := false
+		for _, a := range snapshotAttrs {
+			if field == a.name {
+				_, _, attr := s.Decompose()
+				s = s.Attributes(attr | a.attr)
+				found = true
+			}
+		}
+		if !found {
+			return s, fmt.Errorf("unknown attribute %q", field)
+		}
+	}
+	return
+}
+
+// ConvertSnapshot return versioned text view of cells with runes, colors
+// and attributes of each cell. Example of snapshot:
+//
+//	vl snapshot 1
+//	rows 2
+//	style A fg:black bg:white
+//	style B fg:black bg:yellow bold
+//	0001 "Text" AAAA
+//	0002 "Add " BBBA
+//
+// Each style has single key. Row is number of row, quoted runes and
+// keys of styles for each cell.
+// Snapshot may be loaded by function ParseSnapshot.
+func ConvertSnapshot(cells [][]Cell) string {
+	var (
+		keys   = map[tcell.Style]rune{}
+		styles []tcell.Style
+		rows   strings.Builder
+	)
+	for r := range cells {
+		var (
+			runes = make([]rune, len(cells[r]))
+			line  = make([]rune, len(cells[r]))
+		)
+		for c := range cells[r] {
+			runes[c] = cells[r][c].R
+			key, ok := keys[cells[r][c].S]
+			if !ok {
+				key = snapshotKey(len(styles))
+				keys[cells[r][c].S] = key
+				styles = append(styles, cells[r][c].S)
+			}
+			line[c] = key
+		}
+		fmt.Fprintf(&rows, "%04d %s %s\n", r+1, strconv.Quote(string(runes)), string(line))
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "vl snapshot %d\n", SnapshotVersion)
+	fmt.Fprintf(&buf, "rows %d\n", len(cells))
+	for i, s := range styles {
+		fmt.Fprintf(&buf, "style %c %s\n", snapshotKey(i), styleString(s))
+	}
+	buf.WriteString(rows.String())
+	return buf.String()
+}
+
+// ParseSnapshot return cells from snapshot created by ConvertSnapshot
+func ParseSnapshot(snapshot string) (cells [][]Cell, err error) {
+	lines := strings.Split(strings.TrimSuffix(snapshot, "\n"), "\n")
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("cannot parse snapshot: %w", err)
+		}
+	}()
+	var version, rows int
+	if _, err = fmt.Sscanf(lines[0], "vl snapshot %d", &version); err != nil {
+		return nil, fmt.Errorf("line 1: not valid header %q", lines[0])
+	}
+	if version < 1 || SnapshotVersion < version {
+		return nil, fmt.Errorf("line 1: not supported version %d", version)
+	}
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("line 2: amount of rows is not found")
+	}
+	if _, err = fmt.Sscanf(lines[1], "rows %d", &rows); err != nil || rows < 0 {
+		return nil, fmt.Errorf("line 2: not valid amount of rows %q", lines[1])
+	}
+	styles := map[rune]tcell.Style{}
+	for i := 2; i < len(lines); i++ {
+		line := lines[i]
+		if rest, ok := strings.CutPrefix(line, "style "); ok {
+			key, size := utf8.DecodeRuneInString(rest)
+			if size == 0 || len(rest) == size || rest[size] != ' ' {
+				return nil, fmt.Errorf("line %d: not valid style %q", i+1, line)
+			}
+			if styles[key], err = parseStyle(rest[size+1:]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			continue
+		}
+		index, rest, ok := strings.Cut(line, " ")
+		if row, err := strconv.Atoi(index); !ok || err != nil || row != len(cells)+1 {
+			return nil, fmt.Errorf("line %d: not valid row %q", i+1, line)
+		}
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		text, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		var (
+			runes = []rune(text)
+			keys  = []rune(strings.TrimPrefix(rest[len(quoted):], " "))
+		)
+		if len(runes) != len(keys) {
+			return nil, fmt.Errorf("line %d: amount of runes %d and styles %d is not same",
+				i+1, len(runes), len(keys))
+		}
+		row := make([]Cell, len(runes))
+		for c := range runes {
+			s, ok := styles[keys[c]]
+			if !ok {
+				return nil, fmt.Errorf("line %d: style %q is not found", i+1, keys[c])
+			}
+			row[c] = Cell{S: s, R: runes[c]}
+		}
+		cells = append(cells, row)
+	}
+	if len(cells) != rows {
+		return nil, fmt.Errorf("amount of rows %d is not same %d", len(cells), rows)
+	}
+	return cells, nil
+}
+
+// DiffCells return list of differences between cells. Numbering of
+// rows and columns are from 1 like in snapshot.
+func DiffCells(actual, expect [][]Cell) (diffs []string) {
+	if len(actual) != len(expect) {
+		diffs = append(diffs, fmt.Sprintf("rows: actual %d, expect %d",
+			len(actual), len(expect)))
+	}
+	for r := 0; r < len(actual) && r < len(expect); r++ {
+		if len(actual[r]) != len(expect[r]) {
+			diffs = append(diffs, fmt.Sprintf("row %d: width actual %d, expect %d",
+				r+1, len(actual[r]), len(expect[r])))
+		}
+		for c := 0; c < len(actual[r]) && c < len(expect[r]); c++ {
+			a, e := actual[r][c], expect[r][c]
+			if a == e {
+				continue
+			}
+			diffs = append(diffs, fmt.Sprintf("row %d col %d: actual %q %s, expect %q %s",
+				r+1, c+1, a.R, styleString(a.S), e.R, styleString(e.S)))
+		}
+	}
+	return
+}
+
+// exportRun is cells with same style in row
+type exportRun struct {
+	col   int
+	text  string
+	size  int
+	fg    string
+	bg    string
+	attrs tcell.AttrMask
+}
+
+// exportRuns return runs of cells with same styles in row
+func exportRuns(row []Cell) (runs []exportRun) {
+	for c := 0; c < len(row); {
+		end := c + 1
+		for end < len(row) && row[end].S == row[c].S {
+			end++
+		}
+		var text strings.Builder
+		for _, cell := range row[c:end] {
+			if unicode.IsControl(cell.R) {
+				cell.R = ' '
+			}
+			text.WriteRune(cell.R)
+		}
+		fg, bg, attrs := row[c].S.Decompose()
+		run := exportRun{
+			col:   c,
+			text:  text.String(),
+			size:  end - c,
+			fg:    exportColor(fg, "#000000"),
+			bg:    exportColor(bg, "#ffffff"),
+			attrs: attrs,
+		}
+		if attrs&tcell.AttrReverse != 0 {
+			run.fg, run.bg = run.bg, run.fg
+		}
+		runs = append(runs, run)
+		c = end
+	}
+	return
+}
+
+// exportColor return hex view of color or color by default
+func exportColor(c tcell.Color, def string) string {
+	hex := c.Hex()
+	if hex < 0 {
+		return def
+	}
+	return fmt.Sprintf("#%06x", hex)
+}
+
+// ExportHTML write cells as standalone HTML page
+func ExportHTML(w io.Writer, cells [][]Cell) error {
+	var buf strings.Builder
+	buf.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	buf.WriteString("<title>vl</title>\n</head>\n<body>\n")
+	buf.WriteString("<pre style=\"font-family:monospace;line-height:1.2;" +
+		"color:#000000;background-color:#ffffff;display:inline-block;\">")
+	for r := range cells {
+		if 0 < r {
+			buf.WriteString("\n")
+		}
+		for _, run := range exportRuns(cells[r]) {
+			fmt.Fprintf(&buf, "<span style=\"color:%s;background-color:%s;", run.fg, run.bg)
+			if run.attrs&tcell.AttrBold != 0 {
+				buf.WriteString("font-weight:bold;")
+			}
+			if run.attrs&tcell.AttrItalic != 0 {
+				buf.WriteString("font-style:italic;")
+			}
+			if run.attrs&tcell.AttrDim != 0 {
+				buf.WriteString("opacity:0.5;")
+			}
+			if decoration := exportDecoration(run.attrs); decoration != "" {
+				fmt.Fprintf(&buf, "text-decoration:%s;", decoration)
+			}
+			fmt.Fprintf(&buf, "\">%s</span>", html.EscapeString(run.text))
+		}
+	}
+	buf.WriteString("</pre>\n</body>\n</html>\n")
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+// exportDecoration return text decoration for attributes
+func exportDecoration(attrs tcell.AttrMask) string {
+	var decorations []string
+	if attrs&tcell.AttrUnderline != 0 {
+		decorations = append(decorations, "underline")
+	}
+	if attrs&tcell.AttrStrikeThrough != 0 {
+		decorations = append(decorations, "line-through")
+	}
+	return strings.Join(decorations, " ")
+}
+
+// Size of cell in SVG image
+var (
+	SVGCellWidth  uint = 8
+	SVGCellHeight uint = 16
+)
+
+// ExportSVG write cells as SVG image
+func ExportSVG(w io.Writer, cells [][]Cell) error {
+	var width int
+	for r := range cells {
+		if width < len(cells[r]) {
+			width = len(cells[r])
+		}
+	}
+	var (
+		cw = int(SVGCellWidth)
+		ch = int(SVGCellHeight)
+	)
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "<svg xmlns=\"http://www.w3.org/2000/svg\" "+
+		"width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" "+
+		"font-family=\"monospace\" font-size=\"%d\">\n",
+		width*cw, len(cells)*ch, width*cw, len(cells)*ch, ch*7/8)
+	fmt.Fprintf(&buf, "<rect width=\"100%%\" height=\"100%%\" fill=\"#ffffff\"/>\n")
+	for r := range cells {
+		for _, run := range exportRuns(cells[r]) {
+			fmt.Fprintf(&buf, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+				run.col*cw, r*ch, run.size*cw, ch, run.bg)
+		}
+		for _, run := range exportRuns(cells[r]) {
+			if strings.TrimSpace(run.text) == "" {
+				continue
+			}
+			fmt.Fprintf(&buf, "<text x=\"%d\" y=\"%d\" fill=\"%s\" textLength=\"%d\" "+
+				"lengthAdjust=\"spacingAndGlyphs\" xml:space=\"preserve\"",
+				run.col*cw, r*ch+ch*3/4, run.fg, run.size*cw)
+			if run.attrs&tcell.AttrBold != 0 {
+				buf.WriteString(" font-weight=\"bold\"")
+			}
+			if run.attrs&tcell.AttrItalic != 0 {
+				buf.WriteString(" font-style=\"italic\"")
+			}
+			if run.attrs&tcell.AttrDim != 0 {
+				buf.WriteString(" opacity=\"0.5\"")
+			}
+			if decoration := exportDecoration(run.attrs); decoration != "" {
+				fmt.Fprintf(&buf, " text-decoration=\"%s\"", decoration)
+			}
+			fmt.Fprintf(&buf, ">%s</text>\n", html.EscapeString(run.text))
+		}
+	}
+	buf.WriteString("</svg>\n")
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
 // Render ...
 // snippet render.doc
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
@@ -287,6 +1068,12 @@ func (screen *Screen) Render(width uint, dr Drawer) (height uint) {
 	defer func() {
 		screen.StoreSize(width, height)
 	}()
+	// environment of widgets on screen
+	if screen.sc == nil || screen.sc.screen != screen {
+		screen.sc = &scope{screen: screen}
+	}
+	screen.sc.theme = screen.GetTheme()
+	theme := screen.sc.theme
 	if width == 0 {
 		return
 	}
@@ -298,11 +1085,11 @@ func (screen *Screen) Render(width uint, dr Drawer) (height uint) {
 	if screen.fill == nil {
 		for col := uint(0); col < width; col++ {
 			for row := uint(0); row < screen.hmax; row++ {
-				dr(row, col, ScreenStyle, ' ')
+				dr(row, col, theme.ScreenStyle, ' ')
 			}
 		}
 	} else {
-		screen.fill(' ', ScreenStyle)
+		screen.fill(' ', theme.ScreenStyle)
 	}
 	// draw root widget
 	draw := func(row, col uint, s tcell.Style, r rune) {
@@ -314,20 +1101,59 @@ func (screen *Screen) Render(width uint, dr Drawer) (height uint) {
 		}
 		dr(row, col, s, r)
 	}
+	// dim widgets behind active dialog
+	dim := func(row, col uint, s tcell.Style, r rune) {
+		draw(row, col, s.Dim(true), r)
+	}
 	if screen.root != nil {
-		_ = screen.root.Render(width, draw) // ignore height
-	}
-	// draw dialog
-	// if d := screen.dialog.Root; d != nil {
-	// 	_ = d.Render(width, draw)
-	// 	if c, ok := d.(*container); ok {
-	// 		screen.dialog.offsetX = (width - c.width) / 2
-	// 		screen.dialog.offsetY = (screen.hmax - c.height) / 2
-	// 	}
-	// }
+		if len(screen.dialogs) == 0 {
+			_ = screen.pass(screen.root).Render(width, draw) // ignore height
+		} else {
+			_ = screen.pass(screen.root).Render(width, dim) // ignore height
+		}
+	}
+	// draw dialogs
+	for i, d := range screen.dialogs {
+		if i == len(screen.dialogs)-1 {
+			screen.renderDialog(d, width, draw)
+		} else {
+			screen.renderDialog(d, width, dim)
+		}
+	}
+	// draw context menu above all
+	if screen.popup != nil {
+		screen.pass(screen.popup)
+		screen.popup.renderPopup(width, screen.hmax, draw)
+	}
 	return screen.hmax
 }
 
+// renderDialog draw dialog at the center of screen
+func (screen *Screen) renderDialog(d *dialog, width uint, dr Drawer) {
+	w := d.width
+	if w == 0 {
+		w = dialogWidth
+	}
+	if width < w {
+		w = width
+	}
+	// calculate height of dialog
+	_ = screen.pass(&d.frame).Render(w, NilDrawer)
+	_, h := d.frame.GetSize()
+	if screen.hmax < h {
+		d.frame.SetHeight(screen.hmax)
+		h = screen.hmax
+	}
+	d.offset.row = (screen.hmax - h) / 2
+	d.offset.col = (width - w) / 2
+	_ = screen.pass(&d.frame).Render(w, DrawerLimit(
+		dr,
+		d.offset.row, d.offset.col,
+		d.offset.row, d.offset.row+h-1,
+		d.offset.col, d.offset.col+w-1,
+	))
+}
+
 // SetHeight ...
 // snippet setheight.doc
 // Store maximal height of widget.
@@ -339,11 +1165,6 @@ func (screen *Screen) SetHeight(hmax uint) {
 			screen.root.(VerticalFix).SetHeight(hmax)
 		}
 	}
-	//	if screen.dialog.root != nil {
-	//		if _, ok := screen.dialog.root.(VerticalFix); ok {
-	//			screen.dialog.root.(VerticalFix).SetHeight(hmax)
-	//		}
-	//	}
 }
 
 // Event ...
@@ -351,40 +1172,567 @@ func (screen *Screen) SetHeight(hmax uint) {
 // For create action for widget
 // end event.doc
 func (screen *Screen) Event(ev tcell.Event) {
-	if screen.root == nil {
+	if screen.root == nil && len(screen.dialogs) == 0 {
 		return
 	}
-	// if screen.dialog.root != nil {
-	// 	screen.dialog.root.Event(ev)
-	// 	return
-	// }
-	screen.root.Event(ev)
-}
-
-// func (screen *Screen) Close() {
-// 	screen.dialog.root = nil
-// }
-//
-// func (screen *Screen) AddDialog(name string, dialog Widget) {
-// 	var frame Frame
-// 	frame.Header = TextStatic(name)
-// 	var list List
-// 	if dialog != nil {
-// 		list.Add(dialog)
-// 	}
-// 	var btn Button
-// 	btn.Compress = true
-// 	btn.OnClick = func() {
-// 		screen.Close()
-// 	}
-// 	list.Add(&btn)
-// 	frame.root = &list
-// 	frame.SetHeight(screen.hmax)
-// 	screen.dialog.root = &frame
-// }
-
-///////////////////////////////////////////////////////////////////////////////
-
+	focused, dialogs := screen.focused, len(screen.dialogs)
+	popup, drag := screen.popup, screen.drag
+	defer func() {
+		if screen.focused != focused || len(screen.dialogs) != dialogs ||
+			screen.popup != popup || screen.drag != drag ||
+			drag != nil || popup != nil || screen.affects(ev) {
+			screen.MarkDirty()
+		}
+	}()
+	if screen.pasteEvent(ev) {
+		return
+	}
+	if ev, ok := ev.(*tcell.EventMouse); ok {
+		if screen.drag != nil {
+			screen.dragEvent(ev)
+			return
+		}
+		if ev.Buttons()&mouseButtons != 0 {
+			defer screen.startDrag(ev)()
+		}
+	}
+	if screen.popup != nil {
+		screen.popupEvent(ev)
+		return
+	}
+	if ev, ok := ev.(*tcell.EventMouse); ok && ev.Buttons() == tcell.Button2 {
+		col, row := ev.Position()
+		defer screen.openPopup(row, col)
+	}
+	if ev, ok := ev.(*tcell.EventKey); ok {
+		switch ev.Key() {
+		case tcell.KeyTab:
+			screen.FocusNext()
+			return
+		case tcell.KeyBacktab:
+			screen.FocusPrev()
+			return
+		}
+	}
+	defer screen.fixFocus()
+	if len(screen.dialogs) == 0 {
+		screen.root.Event(ev)
+		return
+	}
+	// all events are trapped by active dialog
+	d := screen.dialogs[len(screen.dialogs)-1]
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+		if ev.Key() == tcell.KeyEscape && !screen.acceptEscape() {
+			screen.Close()
+			return
+		}
+		d.frame.Event(ev)
+	case *tcell.EventMouse:
+		col, row := ev.Position()
+		col -= int(d.offset.col)
+		row -= int(d.offset.row)
+		width, height := d.frame.GetSize()
+		if col < 0 || row < 0 || int(width) <= col || int(height) <= row {
+			// ignore events outside of dialog
+			return
+		}
+		d.frame.Event(tcell.NewEventMouse(
+			col, row,
+			ev.Buttons(),
+			ev.Modifiers()))
+	default:
+		d.frame.Event(ev)
+	}
+}
+
+// affects return true if event `ev` is sent to widgets of screen and
+// may change them
+func (screen *Screen) affects(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+		return screen.paste == nil && screen.focused != nil
+	case *tcell.EventPaste:
+		return ev.End() && screen.focused != nil
+	case *tcell.EventMouse:
+		if ev.Buttons() == tcell.ButtonNone {
+			return false
+		}
+		var w Widget = screen.root
+		var offset Offset
+		if 0 < len(screen.dialogs) {
+			d := screen.dialogs[len(screen.dialogs)-1]
+			w, offset = &d.frame, d.offset
+		}
+		col, row := ev.Position()
+		col -= int(offset.col)
+		row -= int(offset.row)
+		width, height := w.GetSize()
+		return 0 <= col && 0 <= row && col < int(width) && row < int(height)
+	}
+	return true
+}
+
+// acceptEscape return true if focused widget or one of parents use
+// key Escape at the moment, for example for closing of opened submenu
+func (screen *Screen) acceptEscape() bool {
+	for _, path := range focusPaths(screen.active()) {
+		if path[len(path)-1] != screen.focused {
+			continue
+		}
+		for _, w := range path {
+			if e, ok := w.(interface{ AcceptEscape() bool }); ok && e.AcceptEscape() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// openPopup open context menu requested by right mouse button
+func (screen *Screen) openPopup(row, col int) {
+	if row < 0 || col < 0 {
+		return
+	}
+	// the deepest context menu is used
+	var cm *ContextMenu
+	walk(screen.active(), func(w Widget) {
+		if c, ok := w.(*ContextMenu); ok && c.request {
+			c.request = false
+			cm = c
+		}
+	})
+	if cm == nil {
+		return
+	}
+	cm.open(uint(row), uint(col))
+	screen.popup = cm
+}
+
+// popupEvent send event to opened context menu
+func (screen *Screen) popupEvent(ev tcell.Event) {
+	cm := screen.popup
+	switch ev := ev.(type) {
+	case *tcell.EventMouse:
+		if ev.Buttons() == tcell.ButtonNone {
+			// ignore mouse motion
+			break
+		}
+		cm.holder.Event(ev)
+	default:
+		cm.holder.Event(ev)
+	}
+	if !cm.IsOpen() {
+		screen.popup = nil
+	}
+}
+
+// EventPasteText is text pasted in terminal. Screen collect keys between
+// start and end of bracketed paste and send text to focused widget.
+type EventPasteText struct {
+	tcell.EventTime
+	Text string
+}
+
+// pasteEvent collect pasted text and return true if event is part of paste
+func (screen *Screen) pasteEvent(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *tcell.EventPaste:
+		if ev.Start() {
+			screen.paste = new(strings.Builder)
+			return true
+		}
+		if screen.paste == nil {
+			return true
+		}
+		text := screen.paste.String()
+		screen.paste = nil
+		if text == "" || screen.focused == nil {
+			return true
+		}
+		paste := &EventPasteText{Text: text}
+		paste.SetEventNow()
+		screen.focused.Event(paste)
+		return true
+	case *tcell.EventKey:
+		if screen.paste == nil {
+			return false
+		}
+		switch ev.Key() {
+		case tcell.KeyRune:
+			screen.paste.WriteRune(ev.Rune())
+		case tcell.KeyEnter, tcell.KeyLF:
+			screen.paste.WriteRune('\n')
+		case tcell.KeyTab:
+			screen.paste.WriteRune('\t')
+		}
+		return true
+	}
+	return false
+}
+
+// DragState is state of mouse drag
+type DragState uint8
+
+const (
+	DragStart DragState = iota // button is pressed
+	DragMove                   // mouse is moved with pressed button
+	DragEnd                    // button is released
+)
+
+// EventDrag is event of mouse drag. Event is sent only to widget
+// captured mouse by function CaptureMouse.
+type EventDrag struct {
+	tcell.EventTime
+	State    DragState
+	Buttons  tcell.ButtonMask // pressed buttons at start of drag
+	col, row int
+}
+
+// Position return position of mouse relative to widget
+func (ev *EventDrag) Position() (col, row int) {
+	return ev.col, ev.row
+}
+
+// mouseButtons is mask of mouse buttons without wheel
+const mouseButtons = tcell.Button1 | tcell.Button2 | tcell.Button3
+
+// mouseCapture is widget captured mouse at button press
+type mouseCapture struct {
+	widget     Widget
+	col, row   int // position of press on screen
+	dcol, drow int // position of widget on screen
+	buttons    tcell.ButtonMask
+}
+
+// event return drag event for position on screen
+func (c *mouseCapture) event(state DragState, col, row int) *EventDrag {
+	ev := &EventDrag{
+		State:   state,
+		Buttons: c.buttons,
+		col:     col - c.dcol,
+		row:     row - c.drow,
+	}
+	ev.SetEventNow()
+	return ev
+}
+
+// CaptureMouse send rest of gesture started by button press `ev` to
+// widget `w` as EventDrag with position relative to widget.
+// Function is called inside Event of widget of this package.
+// Widgets of other packages use Screen.CaptureMouse.
+func CaptureMouse(w Widget, ev *tcell.EventMouse) {
+	s, ok := w.(interface{ screen() *Screen })
+	if !ok || s.screen() == nil {
+		return
+	}
+	s.screen().CaptureMouse(w, ev)
+}
+
+// CaptureMouse send rest of gesture started by button press `ev` to
+// widget `w` as EventDrag with position relative to widget.
+// Function is called inside Event of widget at button press.
+func (screen *Screen) CaptureMouse(w Widget, ev *tcell.EventMouse) {
+	capture := screen.capture
+	if capture == nil || w == nil || ev == nil {
+		return
+	}
+	col, row := ev.Position()
+	capture.widget = w
+	capture.dcol = capture.col - col
+	capture.drow = capture.row - row
+}
+
+// startDrag prepare capture of mouse by button press and return
+// function for start of drag
+func (screen *Screen) startDrag(ev *tcell.EventMouse) (start func()) {
+	col, row := ev.Position()
+	prev := screen.capture
+	screen.capture = &mouseCapture{col: col, row: row, buttons: ev.Buttons() & mouseButtons}
+	return func() {
+		c := screen.capture
+		screen.capture = prev
+		if c.widget == nil {
+			return
+		}
+		screen.drag = c
+		c.widget.Event(c.event(DragStart, c.col, c.row))
+	}
+}
+
+// dragEvent send mouse event to widget captured mouse
+func (screen *Screen) dragEvent(ev *tcell.EventMouse) {
+	d := screen.drag
+	state := DragMove
+	switch {
+	case ev.Buttons() == tcell.ButtonNone:
+		state = DragEnd
+		screen.drag = nil
+	case ev.Buttons()&mouseButtons == 0:
+		// ignore wheel
+		return
+	}
+	col, row := ev.Position()
+	d.widget.Event(d.event(state, col, row))
+}
+
+// AddDialog show modal dialog with header `name` above root widget.
+// Dialog is closed by key Escape or by function Close.
+func (screen *Screen) AddDialog(name string, root Widget) {
+	d := new(dialog)
+	d.frame.Header = TextStatic(name)
+	d.frame.SetRoot(root)
+	d.focused = screen.focused
+	screen.dialogs = append(screen.dialogs, d)
+	// focus first widget of dialog
+	screen.focused = nil
+	d.frame.Focus(true)
+	screen.FocusNext()
+}
+
+// SetDialogWidth set maximal width of active dialog window.
+// If width is zero, then default width is used.
+func (screen *Screen) SetDialogWidth(width uint) {
+	if len(screen.dialogs) == 0 {
+		return
+	}
+	screen.dialogs[len(screen.dialogs)-1].width = width
+}
+
+// Close remove active dialog and restore focus of widgets behind
+func (screen *Screen) Close() {
+	if len(screen.dialogs) == 0 {
+		return
+	}
+	d := screen.dialogs[len(screen.dialogs)-1]
+	screen.dialogs = screen.dialogs[:len(screen.dialogs)-1]
+	d.frame.Focus(false)
+	screen.focused = d.focused
+	for _, path := range focusPaths(screen.active()) {
+		if path[len(path)-1] == screen.focused {
+			screen.setFocus(path)
+			return
+		}
+	}
+}
+
+// active return widget which take events
+func (screen *Screen) active() Widget {
+	if len(screen.dialogs) == 0 {
+		return screen.root
+	}
+	return &screen.dialogs[len(screen.dialogs)-1].frame
+}
+
+// Focus ...
+// snippet focus.doc
+// For changing focus-state of widget
+// end focus.doc
+func (screen *Screen) Focus(focus bool) {
+	if !focus {
+		if screen.root != nil {
+			screen.root.Focus(focus)
+		}
+		for _, d := range screen.dialogs {
+			d.frame.Focus(focus)
+		}
+	}
+	screen.container.Focus(focus)
+}
+
+// Children return internal widgets in focus order
+func (screen *Screen) Children() []Widget {
+	return []Widget{screen.active()}
+}
+
+// FocusNext move keyboard focus to next focusable widget
+func (screen *Screen) FocusNext() {
+	screen.moveFocus(1)
+}
+
+// FocusPrev move keyboard focus to previous focusable widget
+func (screen *Screen) FocusPrev() {
+	screen.moveFocus(-1)
+}
+
+// GetFocused return focused leaf widget or nil
+func (screen *Screen) GetFocused() Widget {
+	return screen.focused
+}
+
+func (screen *Screen) moveFocus(step int) {
+	paths := focusPaths(screen.active())
+	if len(paths) == 0 {
+		return
+	}
+	pos := -1
+	for i := range paths {
+		if leaf := paths[i][len(paths[i])-1]; leaf == screen.focused && isFocused(leaf) {
+			pos = i
+			break
+		}
+	}
+	switch {
+	case pos < 0 && step < 0:
+		pos = len(paths) - 1
+	case pos < 0:
+		pos = 0
+	default:
+		pos = (pos + step + len(paths)) % len(paths)
+	}
+	screen.setFocus(paths[pos])
+}
+
+// setFocus unfocus all widgets and focus each widget of path
+func (screen *Screen) setFocus(path []Widget) {
+	path[0].Focus(false)
+	for _, w := range path {
+		w.Focus(true)
+	}
+	screen.focused = path[len(path)-1]
+}
+
+// fixFocus guarantee single focused leaf widget after event.
+// Mouse click on not focusable widget focus last focused leaf widget
+// again.
+func (screen *Screen) fixFocus() {
+	paths := focusPaths(screen.active())
+	var found []int
+	for i := range paths {
+		if isFocused(paths[i][len(paths[i])-1]) {
+			found = append(found, i)
+		}
+	}
+	switch len(found) {
+	case 0:
+		// restore last focused widget
+		for i := range paths {
+			if paths[i][len(paths[i])-1] == screen.focused {
+				screen.setFocus(paths[i])
+				return
+			}
+		}
+		screen.focused = nil
+	case 1:
+		screen.focused = paths[found[0]][len(paths[found[0]])-1]
+	default:
+		// prefer new focused widget
+		pos := found[len(found)-1]
+		for _, i := range found {
+			if paths[i][len(paths[i])-1] != screen.focused {
+				pos = i
+				break
+			}
+		}
+		screen.setFocus(paths[pos])
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Themed is wrapper of root widget with own theme.
+// Root widget is drawn with theme from SetTheme, or with theme of parent
+// widgets if theme is nil. Function from SetOverride is used for local
+// changes of theme, for example:
+//
+//	var th Themed
+//	th.SetRoot(&button)
+//	th.SetOverride(func(t *Theme) { t.ButtonStyle = t.ButtonSelectStyle })
+type Themed struct {
+	container
+	rootable
+	local    *Theme
+	override func(t *Theme)
+
+	inner *scope // environment of root widget
+	base  *Theme // theme of inner environment before override
+}
+
+// SetTheme set theme of root widget.
+// If theme is nil, then theme of parent widgets is used.
+func (th *Themed) SetTheme(t *Theme) {
+	th.local = t
+	th.inner = nil
+}
+
+// SetOverride set function for local changes of theme
+func (th *Themed) SetOverride(f func(t *Theme)) {
+	th.override = f
+	th.inner = nil
+}
+
+// Focus ...
+// snippet focus.doc
+// For changing focus-state of widget
+// end focus.doc
+func (th *Themed) Focus(focus bool) {
+	th.container.Focus(focus)
+	if th.root != nil {
+		th.root.Focus(focus)
+	}
+}
+
+// IsFocused return focus-state of widget
+func (th *Themed) IsFocused() bool {
+	return isFocused(th.root)
+}
+
+// Children return internal widgets in focus order
+func (th *Themed) Children() []Widget {
+	return []Widget{th.root}
+}
+
+// SetHeight ...
+// snippet setheight.doc
+// Store maximal height of widget.
+// end setheight.doc
+func (th *Themed) SetHeight(hmax uint) {
+	if vf, ok := th.root.(VerticalFix); ok {
+		vf.SetHeight(hmax)
+	}
+}
+
+// Render ...
+// snippet render.doc
+// Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
+// end render.doc
+func (th *Themed) Render(width uint, dr Drawer) (height uint) {
+	defer func() {
+		th.StoreSize(width, height)
+	}()
+	if th.root == nil {
+		return
+	}
+	base := th.theme()
+	if th.local != nil {
+		base = th.local
+	}
+	// rebuild environment only after changes of theme or screen
+	if th.inner == nil || th.base != base || th.inner.screen != th.sc.screen {
+		t := *base
+		if th.override != nil {
+			th.override(&t)
+		}
+		th.inner = &scope{theme: &t, screen: th.sc.screen}
+		th.base = base
+	}
+	setScope(th.root, th.inner)
+	return th.root.Render(width, dr)
+}
+
+// Event ...
+// snippet event.doc
+// For create action for widget
+// end event.doc
+func (th *Themed) Event(ev tcell.Event) {
+	if th.root == nil {
+		return
+	}
+	th.root.Event(ev)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
 // Separator is empty single horizontal line
 type Separator struct{ container }
 
@@ -408,6 +1756,11 @@ type Text struct {
 	maxLines  uint
 	style     *tcell.Style
 	addCursor bool
+	cwidth    uint // width of content
+
+	wordWrap   bool // wrap by words
+	justify    bool // justify text wrapped by words
+	linesLimit uint // minimal visible lines
 }
 
 var DefaultMaxTextLines uint = 5
@@ -424,9 +1777,17 @@ func (t *Text) SetStyle(style *tcell.Style) {
 
 // SetLinesLimit set minimal visible lines of text
 func (t *Text) SetLinesLimit(limit uint) {
+	t.linesLimit = limit
 	t.content.SetLinesLimit(limit)
 }
 
+// SetWordWrap set wrapping of text by words with optional justify.
+// Text with cursor, like InputBox, is always wrapped by runes.
+func (t *Text) SetWordWrap(wrap, justify bool) {
+	t.wordWrap = wrap
+	t.justify = justify
+}
+
 // SetText set to new widget text
 func (t *Text) SetText(str string) {
 	t.content.SetText([]rune(str))
@@ -452,6 +1813,7 @@ func (t *Text) Filter(f func(r rune) (insert bool)) {
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
 // end render.doc
 func (t *Text) Render(width uint, dr Drawer) (height uint) {
+	theme := t.theme()
 	defer func() {
 		t.StoreSize(width, height)
 	}()
@@ -459,18 +1821,23 @@ func (t *Text) Render(width uint, dr Drawer) (height uint) {
 		width, height = 0, 0
 		return
 	}
-	if t.style == nil {
-		t.style = &TextStyle
+	style := t.style
+	if style == nil {
+		style = &theme.TextStyle
+	}
+	t.cwidth = width + 1
+	t.content.SetWidth(t.cwidth)
+	if t.wordWrap && !t.addCursor {
+		return t.renderWords(width, dr, *style)
 	}
-	t.content.SetWidth(width + 1)
 	var cur func(row, col uint) // hide cursor for not-focus inputbox
 	if t.focus && t.addCursor {
 		cur = func(row, col uint) {
 			if width < col {
 				panic("Text width")
 			}
-			st := CursorStyle
-			dr(row, col, st, Cursor)
+			st := theme.CursorStyle
+			dr(row, col, st, theme.Cursor)
 		}
 	}
 
@@ -493,7 +1860,7 @@ func (t *Text) Render(width uint, dr Drawer) (height uint) {
 	// drawing
 	for w := 0; w <= int(width); w++ {
 		for h := 0; h < int(height); h++ {
-			dr(uint(h), uint(w), *t.style, ' ')
+			dr(uint(h), uint(w), *style, ' ')
 		}
 	}
 	draw = func(row, col uint, r rune) {
@@ -509,7 +1876,7 @@ func (t *Text) Render(width uint, dr Drawer) (height uint) {
 		if 0 < t.maxLines && t.maxLines <= row {
 			return
 		}
-		dr(row, col, *t.style, r)
+		dr(row, col, *style, r)
 	}
 	t.content.Render(draw, cur)
 	if 0 < t.maxLines && t.maxLines < height {
@@ -519,10 +1886,54 @@ func (t *Text) Render(width uint, dr Drawer) (height uint) {
 	return
 }
 
+// renderWords draw text wrapped by words
+func (t *Text) renderWords(width uint, dr Drawer, style tcell.Style) (height uint) {
+	runes := t.content.GetText()
+	ps, rows := wrapWords(runes, width, t.justify)
+	height = rows
+	if height == 0 {
+		height = 1
+	}
+	if 0 < t.linesLimit {
+		height = t.linesLimit
+	}
+	if 0 < t.maxLines && t.maxLines < height {
+		height = t.maxLines
+	}
+	if t.compress {
+		size := uint(1)
+		for i := range ps {
+			if !ps[i].hide && size < ps[i].col+1 {
+				size = ps[i].col + 1
+			}
+		}
+		if size < width {
+			width = size + 1
+		}
+	}
+	// drawing
+	for w := 0; w <= int(width); w++ {
+		for h := 0; h < int(height); h++ {
+			dr(uint(h), uint(w), style, ' ')
+		}
+	}
+	for i, r := range runes {
+		if ps[i].hide || height <= ps[i].row {
+			continue
+		}
+		if unicode.IsSpace(r) && r != ' ' {
+			r = '\u2022' // like inside package tf
+		}
+		dr(ps[i].row, ps[i].col, style, r)
+	}
+	return
+}
+
 // /////////////////////////////////////////////////////////////////////////////
 type Static struct {
 	Image
 	lastWidth uint
+	lastTheme Theme // theme of rendered image
 	rootable
 }
 
@@ -540,10 +1951,12 @@ func (s *Static) Compress() {
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
 // end render.doc
 func (s *Static) Render(width uint, dr Drawer) (height uint) {
-	if width != s.lastWidth {
+	theme := s.theme()
+	if width != s.lastWidth || *theme != s.lastTheme {
 		s.lastWidth = width
+		s.lastTheme = *theme
 		// rendering image and show
-		s.root.Render(width, NilDrawer)
+		s.pass(s.root).Render(width, NilDrawer)
 		width, height = s.root.GetSize()
 		img := &s.Image.data
 		if width == 0 || height == 0 {
@@ -554,13 +1967,13 @@ func (s *Static) Render(width uint, dr Drawer) (height uint) {
 				for i := uint(0); i < uint(len(*img)); i++ {
 					(*img)[i] = make([]Cell, width)
 				}
-				for i := range *img {
-					for j := range (*img)[i] {
-						(*img)[i][j] = Cell{S: ScreenStyle, R: ' '}
-					}
+			}
+			for i := range *img {
+				for j := range (*img)[i] {
+					(*img)[i][j] = Cell{S: theme.ScreenStyle, R: ' '}
 				}
 			}
-			s.root.Render(width, func(row, col uint, s tcell.Style, r rune) {
+			s.pass(s.root).Render(width, func(row, col uint, s tcell.Style, r rune) {
 				if col == width {
 					return
 				}
@@ -581,6 +1994,17 @@ func TextStatic(str string) Widget {
 	return t
 }
 
+// TextStaticWrap return static text wrapped by words with optional justify
+func TextStaticWrap(str string, justify bool) Widget {
+	txt := new(Text)
+	txt.content.SetText([]rune(str))
+	txt.SetWordWrap(true, justify)
+	txt.Compress()
+	t := new(Static)
+	t.SetRoot(txt)
+	return t
+}
+
 // /////////////////////////////////////////////////////////////////////////////
 const scrollBarWidth uint = 1
 
@@ -588,6 +2012,7 @@ type Scroll struct {
 	ContainerVerticalFix
 	rootable
 	offset uint
+	thumb  uint // row of scrollbar thumb
 }
 
 // Focus ...
@@ -602,11 +2027,17 @@ func (sc *Scroll) Focus(focus bool) {
 	sc.root.Focus(focus)
 }
 
+// Children return internal widgets in focus order
+func (sc *Scroll) Children() []Widget {
+	return []Widget{sc.root}
+}
+
 // Render ...
 // snippet render.doc
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
 // end render.doc
 func (sc *Scroll) Render(width uint, dr Drawer) (height uint) {
+	theme := sc.theme()
 	defer func() {
 		sc.StoreSize(width, height)
 	}()
@@ -634,7 +2065,7 @@ func (sc *Scroll) Render(width uint, dr Drawer) (height uint) {
 		if maxSize < sc.hmax {
 			panic(fmt.Errorf("too big sc.hmax: %d", sc.hmax))
 		}
-		height = sc.root.Render(width-scrollBarWidth, draw)
+		height = sc.pass(sc.root).Render(width-scrollBarWidth, draw)
 		// calculate location
 		if 2 < sc.hmax {
 			var value float32 // 0 ... 1
@@ -649,12 +2080,12 @@ func (sc *Scroll) Render(width uint, dr Drawer) (height uint) {
 			if value < 0 {
 				value = 0.0
 			}
-			st := TextStyle
+			st := theme.TextStyle
 			for r := uint(0); r < sc.hmax; r++ {
-				dr(r, width-scrollBarWidth, st, ScrollLine)
+				dr(r, width-scrollBarWidth, st, theme.ScrollLine)
 			}
-			dr(0, width-scrollBarWidth, st, ScrollUp)
-			dr(sc.hmax-1, width-scrollBarWidth, st, ScrollDown)
+			dr(0, width-scrollBarWidth, st, theme.ScrollUp)
+			dr(sc.hmax-1, width-scrollBarWidth, st, theme.ScrollDown)
 			pos := uint(value * float32(sc.hmax-2))
 			if pos == 0 {
 				pos = 1
@@ -662,14 +2093,31 @@ func (sc *Scroll) Render(width uint, dr Drawer) (height uint) {
 			if pos == sc.hmax-scrollBarWidth {
 				pos = sc.hmax - 2
 			}
-			dr(pos, width-scrollBarWidth, st, ScrollSquare)
+			dr(pos, width-scrollBarWidth, st, theme.ScrollSquare)
+			sc.thumb = pos
 		}
 	} else {
-		height = sc.root.Render(width, draw)
+		height = sc.pass(sc.root).Render(width, draw)
 	}
 	return
 }
 
+// dragThumb move scrollbar thumb to row
+func (sc *Scroll) dragThumb(row int) {
+	if sc.hmax <= 3 || sc.height <= sc.hmax {
+		return
+	}
+	value := float32(row-1) / float32(sc.hmax-3)
+	if value < 0 {
+		value = 0
+	}
+	if 1 < value {
+		value = 1
+	}
+	sc.offset = uint(value*float32(sc.height-sc.hmax) + 0.5)
+	sc.fixOffset() // fix offset position
+}
+
 func (sc *Scroll) fixOffset() {
 	const minViewLines uint = 2 // constant
 	if sc.height < minViewLines {
@@ -697,6 +2145,13 @@ func (sc *Scroll) Event(ev tcell.Event) {
 	if sc.root == nil {
 		return
 	}
+	if ev, ok := ev.(*EventDrag); ok {
+		if ev.State == DragMove {
+			_, row := ev.Position()
+			sc.dragThumb(row)
+		}
+		return
+	}
 
 	_, ok := sc.onFocus(ev)
 	if ok {
@@ -727,6 +2182,11 @@ func (sc *Scroll) Event(ev tcell.Event) {
 		default:
 			if 0 < row && 2 < sc.hmax && ev.Buttons() == tcell.Button1 &&
 				col == int(sc.width-scrollBarWidth) && 0 < sc.hmax {
+				if uint(row) == sc.thumb {
+					// drag of thumb
+					CaptureMouse(sc, ev)
+					break
+				}
 				ratio := float32(row-1) / float32(sc.hmax-2)
 				dh := float32(sc.height)
 				if 0 < dh {
@@ -820,10 +2280,20 @@ func (l *List) Compress() {
 	l.compress = true
 }
 
-func (l List) getItemHmax() uint {
-	return l.hmax / uint(len(l.nodes))
-}
-
+// Children return internal widgets in focus order
+func (l *List) Children() (ws []Widget) {
+	for i := range l.nodes {
+		if w := l.nodes[i].w; w != nil {
+			ws = append(ws, w)
+		}
+	}
+	return
+}
+
+func (l *List) getItemHmax() uint {
+	return l.hmax / uint(len(l.nodes))
+}
+
 // Render ...
 // snippet render.doc
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
@@ -849,14 +2319,14 @@ func (l *List) Render(width uint, dr Drawer) (height uint) {
 		}
 		// initialize sizes of widgets
 		if l.compress && (l.addlimit && 0 < l.hmax) {
-			l.nodes[i].w.Render(width, NilDrawer)
+			l.pass(l.nodes[i].w).Render(width, NilDrawer)
 			_, h := l.nodes[i].w.GetSize()
 			if l.hmax < h {
 				h = l.hmax
 			}
 			l.nodes[i].to = l.nodes[i].from + int(h)
 		} else if !l.addlimit {
-			l.nodes[i].w.Render(width, NilDrawer)
+			l.pass(l.nodes[i].w).Render(width, NilDrawer)
 			_, h := l.nodes[i].w.GetSize()
 			l.nodes[i].to = l.nodes[i].from + int(h)
 		} else {
@@ -871,7 +2341,7 @@ func (l *List) Render(width uint, dr Drawer) (height uint) {
 			break
 		}
 		// drawing
-		l.nodes[i].w.Render(width, DrawerLimit(
+		l.pass(l.nodes[i].w).Render(width, DrawerLimit(
 			dr,
 			uint(l.nodes[i].from), 0,
 			uint(l.nodes[i].from), uint(l.nodes[i].to)-1,
@@ -938,8 +2408,9 @@ func (l *List) Event(ev tcell.Event) {
 			}
 		}
 	case *tcell.EventKey:
+		// send only to focused widgets
 		for i := range l.nodes {
-			if w := l.nodes[i].w; w != nil {
+			if w := l.nodes[i].w; w != nil && isFocused(w) {
 				w.Event(ev)
 			}
 		}
@@ -991,6 +2462,14 @@ func (l *List) SetHeight(hmax uint) {
 //   - Button
 //   - Checkbox
 //   - RadioGroup
+//
+// Keyboard:
+//   - F10 activate or deactivate menu line
+//   - Alt+letter activate element with mnemonic, for example "&File"
+//   - Left/Right move between elements of menu line
+//   - Down/Enter open submenu
+//   - Up/Down move inside opened submenu
+//   - Escape close one level of submenu
 type Menu struct {
 	ContainerVerticalFix
 	rootable
@@ -1006,6 +2485,32 @@ type Menu struct {
 	offset       Offset
 	parent       *Menu
 	subs         []*Menu
+
+	items   []menuItem // properties of elements in order of adding
+	restore []Widget   // focused widgets of root before menu activation
+}
+
+// menuItem is properties of menu element
+type menuItem struct {
+	key rune  // mnemonic in lower case or 0
+	sub *Menu // submenu or nil
+}
+
+// mnemonic return name without symbol '&' and lower case letter
+// after that symbol. Symbols "&&" is replaced to "&".
+func mnemonic(name string) (text string, key rune) {
+	rs := []rune(name)
+	buf := make([]rune, 0, len(rs))
+	for i := 0; i < len(rs); i++ {
+		if rs[i] == '&' && i+1 < len(rs) {
+			i++
+			if rs[i] != '&' && key == 0 {
+				key = unicode.ToLower(rs[i])
+			}
+		}
+		buf = append(buf, rs[i])
+	}
+	return string(buf), key
 }
 
 // SetHeight ...
@@ -1028,16 +2533,40 @@ func (menu *Menu) fixRootHeight() {
 	}
 }
 
+// Focus ...
+// snippet focus.doc
+// For changing focus-state of widget
+// end focus.doc
+func (menu *Menu) Focus(focus bool) {
+	if !focus {
+		menu.header.Focus(focus)
+		menu.list.Focus(focus)
+		if menu.root != nil {
+			menu.root.Focus(focus)
+		}
+	}
+	menu.container.Focus(focus)
+}
+
+// Children return internal widgets in focus order
+func (menu *Menu) Children() []Widget {
+	if menu.parent != nil {
+		return nil
+	}
+	return []Widget{&menu.header, menu.root}
+}
+
 func (menu *Menu) AddButton(name string, OnClick func()) {
 	if OnClick == nil {
 		menu.AddText(name)
 		return
 	}
 	// prepare element
+	text, key := mnemonic(name)
 	var btn Button
 	// btn.SetMaxLines(1)
 	// btn.SetLinesLimit(1)
-	btn.SetText(name)
+	btn.SetText(text)
 	btn.Compress()
 	btn.OnClick = func() {
 		if f := OnClick; f != nil {
@@ -1048,6 +2577,7 @@ func (menu *Menu) AddButton(name string, OnClick func()) {
 	// adding
 	menu.list.Add(&btn)
 	menu.header.Add(&btn)
+	menu.items = append(menu.items, menuItem{key: key})
 
 	menu.frame.SetRoot(&menu.list)
 }
@@ -1058,6 +2588,7 @@ func (menu *Menu) AddText(name string) {
 	// adding
 	menu.list.Add(txt)
 	menu.header.Add(txt)
+	menu.items = append(menu.items, menuItem{})
 
 	menu.frame.SetRoot(&menu.list)
 }
@@ -1070,10 +2601,11 @@ func (menu *Menu) AddMenu(name string, sub *Menu) {
 		menu.subs[i].parent = menu
 	}
 	// prepare element
+	text, key := mnemonic(name)
 	var btn Button
 	btn.SetMaxLines(1)
 	btn.SetLinesLimit(1)
-	btn.SetText(name)
+	btn.SetText(text)
 	btn.Compress()
 	btn.OnClick = func() {
 		menu.subs[pos].readyForOpen = true
@@ -1081,6 +2613,7 @@ func (menu *Menu) AddMenu(name string, sub *Menu) {
 	// adding
 	menu.list.Add(&btn)
 	menu.header.Add(&btn)
+	menu.items = append(menu.items, menuItem{key: key, sub: sub})
 
 	// menu.frame.Header = TextStatic(name)
 	menu.frame.SetRoot(&menu.list)
@@ -1121,7 +2654,7 @@ func (menu *Menu) Render(width uint, dr Drawer) (height uint) {
 		if SubMenuWidth < w {
 			w = SubMenuWidth
 		}
-		menu.frame.Render(w, DrawerLimit(
+		menu.pass(&menu.frame).Render(w, DrawerLimit(
 			dr,
 			menu.offset.row, menu.offset.col,
 			0, maxSize,
@@ -1130,10 +2663,10 @@ func (menu *Menu) Render(width uint, dr Drawer) (height uint) {
 	}
 	if menu.parent == nil {
 		menu.header.Compress()
-		h := menu.header.Render(width, dr)
+		h := menu.pass(&menu.header).Render(width, dr)
 		if menu.root != nil {
 			menu.fixRootHeight() // fix root
-			height = menu.root.Render(width, DrawerLimit(
+			height = menu.pass(menu.root).Render(width, DrawerLimit(
 				dr,
 				h, 0,
 				0, menu.hmax,
@@ -1153,7 +2686,7 @@ func (menu *Menu) Render(width uint, dr Drawer) (height uint) {
 		if !m.opened {
 			continue
 		}
-		m.Render(width, dr)
+		menu.pass(m).Render(width, dr)
 	}
 	if menu.addlimit && 0 < menu.height {
 		height = menu.hmax
@@ -1166,6 +2699,9 @@ func (menu *Menu) Render(width uint, dr Drawer) (height uint) {
 // For create action for widget
 // end event.doc
 func (menu *Menu) Event(ev tcell.Event) {
+	if _, ok := menu.onFocus(ev); ok {
+		menu.container.Focus(true)
+	}
 	var found bool
 	{
 		switch ev := ev.(type) {
@@ -1180,8 +2716,10 @@ func (menu *Menu) Event(ev tcell.Event) {
 				found = true
 			}
 
-			// TODO case *tcell.EventKey:
-			// TODO 	menu.root.Event(ev)
+		case *tcell.EventKey:
+			if menu.parent == nil && menu.keyEvent(ev) {
+				return
+			}
 		}
 	}
 
@@ -1268,10 +2806,6 @@ func (menu *Menu) Event(ev tcell.Event) {
 				// offser of submenu for good view
 				menu.offset.row++
 			}
-
-			// TODO case *tcell.EventKey:
-			// TODO 	menu.resetSubmenu()
-			// TODO 	// menu.header.Event(ev)
 		}
 	}
 	readyForOpen(menu)
@@ -1293,12 +2827,17 @@ func (menu *Menu) Event(ev tcell.Event) {
 				break
 				// return
 			}
+			menu.header.Focus(false)
 			menu.root.Event(tcell.NewEventMouse(
 				col, row,
 				ev.Buttons(),
 				ev.Modifiers()))
 
 		case *tcell.EventKey:
+			if isFocused(&menu.header) {
+				menu.header.Event(ev)
+				break
+			}
 			menu.root.Event(ev)
 		}
 	}
@@ -1309,18 +2848,265 @@ func (menu *Menu) resetSubmenu() {
 		// recursive event
 		menu.parent.resetSubmenu()
 	}
+	menu.closeSubmenu()
+}
 
+// closeSubmenu close only menu and all submenus of that menu
+func (menu *Menu) closeSubmenu() {
 	menu.readyForOpen = false
 	menu.opened = false
+	if menu.parent != nil {
+		menu.frame.Focus(false)
+	}
 	for i := range menu.subs {
 		if menu.subs[i] == nil {
 			continue
 		}
-		menu.subs[i].readyForOpen = false
-		menu.subs[i].opened = false
+		menu.subs[i].closeSubmenu()
+	}
+}
+
+// AcceptEscape return true if key Escape is used for closing of menu
+func (menu *Menu) AcceptEscape() bool {
+	return menu.opening() != nil || isFocused(&menu.header)
+}
+
+// keyEvent is keyboard navigation of main menu.
+// Return true if event is used.
+func (menu *Menu) keyEvent(ev *tcell.EventKey) bool {
+	open := menu.opening()
+	active := open != nil || isFocused(&menu.header)
+	switch {
+	case ev.Key() == tcell.KeyF10:
+		if active {
+			menu.deactivate()
+		} else {
+			menu.activate()
+			menu.nextElement(1)
+		}
+		return true
+	case ev.Key() == tcell.KeyRune && ev.Modifiers()&tcell.ModAlt != 0:
+		return menu.mnemonicEvent(unicode.ToLower(ev.Rune()), open)
+	}
+	if !active {
+		return false
+	}
+	switch ev.Key() {
+	case tcell.KeyUp:
+		if open != nil {
+			open.nextElement(-1)
+		}
+	case tcell.KeyDown:
+		if open != nil {
+			open.nextElement(1)
+			break
+		}
+		if pos := menu.current(); 0 <= pos && menu.items[pos].sub != nil {
+			menu.openElement(pos)
+		}
+	case tcell.KeyEnter:
+		if open != nil {
+			menu.press(open)
+		} else {
+			menu.press(menu)
+		}
+	case tcell.KeyLeft:
+		if open != nil && open.parent != menu {
+			open.closeSubmenu()
+			break
+		}
+		menu.nextTop(-1, open != nil)
+	case tcell.KeyRight:
+		if open != nil {
+			if pos := open.current(); 0 <= pos && open.items[pos].sub != nil {
+				open.openElement(pos)
+				break
+			}
+		}
+		menu.nextTop(1, open != nil)
+	case tcell.KeyEscape:
+		if open != nil {
+			open.closeSubmenu()
+			break
+		}
+		menu.deactivate()
+	default:
+		// all keys are trapped by opened submenu
+		return open != nil
+	}
+	return true
+}
+
+// mnemonicEvent activate element with mnemonic `key`.
+// Elements of opened submenu are checked first.
+func (menu *Menu) mnemonicEvent(key rune, open *Menu) bool {
+	for _, level := range []*Menu{open, menu} {
+		if level == nil {
+			continue
+		}
+		for pos := range level.items {
+			if level.items[pos].key != key {
+				continue
+			}
+			if level == menu {
+				if !isFocused(&menu.header) {
+					menu.activate()
+				}
+				menu.resetSubmenu()
+			}
+			level.focusElement(pos)
+			menu.press(level)
+			return true
+		}
+	}
+	return false
+}
+
+// activate move keyboard focus from root widget to menu line
+func (menu *Menu) activate() {
+	menu.restore = nil
+	for _, path := range focusPaths(menu.root) {
+		if isFocused(path[len(path)-1]) {
+			menu.restore = path
+			break
+		}
+	}
+	if menu.root != nil {
+		menu.root.Focus(false)
+	}
+	menu.container.Focus(true)
+}
+
+// deactivate close all submenus and return keyboard focus to root widget
+func (menu *Menu) deactivate() {
+	menu.resetSubmenu()
+	menu.header.Focus(false)
+	if len(menu.restore) == 0 {
+		if paths := focusPaths(menu.root); 0 < len(paths) {
+			menu.restore = paths[0]
+		}
+	}
+	for _, w := range menu.restore {
+		w.Focus(true)
+	}
+	menu.restore = nil
+}
+
+// press activate focused element of menu `level`
+func (menu *Menu) press(level *Menu) {
+	pos := level.current()
+	if pos < 0 {
+		return
+	}
+	if level.items[pos].sub != nil {
+		level.openElement(pos)
+		return
+	}
+	level.elements()[pos].w.Event(tcell.NewEventKey(tcell.KeyEnter, '\n', tcell.ModNone))
+	menu.deactivate()
+}
+
+// nextTop move focus to next element of menu line and open
+// submenu of that element if `open` is true
+func (menu *Menu) nextTop(step int, open bool) {
+	menu.resetSubmenu()
+	menu.nextElement(step)
+	if !open {
+		return
+	}
+	if pos := menu.current(); 0 <= pos && menu.items[pos].sub != nil {
+		menu.openElement(pos)
+	}
+}
+
+// opening return deepest opened submenu or nil
+func (menu *Menu) opening() *Menu {
+	for _, sub := range menu.subs {
+		if sub == nil || !sub.opened {
+			continue
+		}
+		if deep := sub.opening(); deep != nil {
+			return deep
+		}
+		return sub
+	}
+	return nil
+}
+
+// elements return nodes of menu line for main menu and
+// nodes of list for submenu
+func (menu *Menu) elements() []listNode {
+	if menu.parent == nil {
+		return menu.header.nodes
+	}
+	return menu.list.nodes
+}
+
+// current return position of focused element or -1
+func (menu *Menu) current() int {
+	for pos, n := range menu.elements() {
+		if f, ok := n.w.(Focusable); ok && f.AcceptFocus() && f.IsFocused() {
+			return pos
+		}
+	}
+	return -1
+}
+
+// focusElement focus only element on position `pos`
+func (menu *Menu) focusElement(pos int) {
+	nodes := menu.elements()
+	if pos < 0 || len(nodes) <= pos {
+		return
+	}
+	if menu.parent == nil {
+		menu.header.Focus(false)
+		menu.header.Focus(true)
+	} else {
+		menu.list.Focus(false)
+		menu.list.Focus(true)
+	}
+	nodes[pos].w.Focus(true)
+}
+
+// nextElement move focus to next focusable element
+func (menu *Menu) nextElement(step int) {
+	nodes := menu.elements()
+	pos := menu.current()
+	if pos < 0 && step < 0 {
+		pos = 0
+	}
+	for range nodes {
+		pos = (pos + step + len(nodes)) % len(nodes)
+		if f, ok := nodes[pos].w.(Focusable); ok && f.AcceptFocus() {
+			menu.focusElement(pos)
+			return
+		}
 	}
 }
 
+// openElement open submenu of element on position `pos`
+// below that element
+func (menu *Menu) openElement(pos int) {
+	sub := menu.items[pos].sub
+	n := menu.elements()[pos]
+	sub.closeSubmenu()
+	if menu.parent == nil {
+		sub.offset = Offset{
+			row: menu.header.height,
+			col: uint(n.from),
+		}
+	} else {
+		sub.offset = Offset{
+			row: menu.offset.row + menu.frame.offsetRoot.row + uint(n.from) + 1,
+			col: menu.offset.col + menu.frame.offsetRoot.col + 1,
+		}
+	}
+	sub.opened = true
+	sub.container.Focus(true)
+	sub.frame.Focus(true)
+	sub.nextElement(1)
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 
 // Button examples
@@ -1346,6 +3132,7 @@ type Button struct {
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
 // end render.doc
 func (b *Button) Render(width uint, dr Drawer) (height uint) {
+	theme := b.theme()
 	defer func() {
 		b.StoreSize(width, height)
 	}()
@@ -1354,9 +3141,9 @@ func (b *Button) Render(width uint, dr Drawer) (height uint) {
 		return
 	}
 	// default style
-	st := &ButtonStyle
+	st := &theme.ButtonStyle
 	if b.focus {
-		st = &ButtonFocusStyle
+		st = &theme.ButtonFocusStyle
 	}
 	b.Text.style = st
 	// constant
@@ -1396,8 +3183,16 @@ func (b *Button) Event(ev tcell.Event) {
 	if mouse[0] && b.OnClick != nil {
 		b.OnClick()
 	}
+	if ev, ok := ev.(*tcell.EventKey); ok && b.focus {
+		if ev.Key() == tcell.KeyEnter && b.OnClick != nil {
+			b.OnClick()
+		}
+	}
 }
 
+// AcceptFocus return true if widget may be focused by keyboard
+func (b *Button) AcceptFocus() bool { return true }
+
 ///////////////////////////////////////////////////////////////////////////////
 
 type rootable struct{ root Widget }
@@ -1415,6 +3210,91 @@ type word struct {
 	R []rune
 }
 
+// wrapPosition is position of rune in text wrapped by words
+type wrapPosition struct {
+	row, col uint
+	hide     bool // rune is not shown, for example space at wrapping
+}
+
+// wrapWords return positions of runes wrapped by words inside width and
+// amount of rows. Rows are broken at spaces and after hyphens, word is
+// split only if it is longer than width. Spaces of rows, except last
+// row of paragraph, are extended up to width if justify is true.
+func wrapWords(runes []rune, width uint, justify bool) (ps []wrapPosition, rows uint) {
+	ps = make([]wrapPosition, len(runes))
+	if width == 0 {
+		for i := range ps {
+			ps[i].hide = true
+		}
+		return
+	}
+	for from := 0; from < len(runes); rows++ {
+		end, next := len(runes), len(runes)
+		paragraph := true // row is last row of paragraph
+		for i := from; i < len(runes); i++ {
+			if runes[i] == '\n' {
+				end, next = i, i+1
+				break
+			}
+			if i-from < int(width) {
+				continue
+			}
+			// rune is outside of row
+			end, next = i, i
+			paragraph = false
+			for b := i; from < b; b-- {
+				if runes[b] == ' ' {
+					end, next = b, b
+					break
+				}
+				if runes[b-1] == '-' {
+					end, next = b, b
+					break
+				}
+			}
+			// spaces at the end of row
+			for from < end && runes[end-1] == ' ' {
+				end--
+			}
+			for next < len(runes) && runes[next] == ' ' {
+				next++
+			}
+			if end == from {
+				end, next = i, i
+			}
+			break
+		}
+		// extra spaces for justify
+		var gaps []int
+		if justify && !paragraph {
+			for k := from + 1; k < end; k++ {
+				if runes[k] == ' ' && runes[k-1] != ' ' {
+					gaps = append(gaps, k)
+				}
+			}
+		}
+		extra := int(width) - (end - from)
+		var shift, gap int
+		for k := from; k < next; k++ {
+			if gap < len(gaps) && k == gaps[gap] {
+				shift += extra / len(gaps)
+				if gap < extra%len(gaps) {
+					shift++
+				}
+				gap++
+			}
+			ps[k] = wrapPosition{row: rows, col: uint(k - from + shift), hide: end <= k}
+		}
+		from = next
+	}
+	if 0 < len(runes) && runes[len(runes)-1] == '\n' {
+		rows++
+	}
+	return
+}
+
+// Colorize return styles of words or styles of all runes of words for
+// styling of part of word. Nil style is not changed style.
 type Colorize func(words []string) []*tcell.Style
 
 func TypicalColorize(indicates []string, t tcell.Style) Colorize {
@@ -1508,24 +3388,130 @@ func TypicalColorize(indicates []string, t tcell.Style) Colorize {
 	}
 }
 
+// RegexpStyle is style of matches of regular expression
+type RegexpStyle struct {
+	Regexp *regexp.Regexp
+	// Style of whole match, if Groups is empty
+	Style tcell.Style
+	// Groups is styles of capture groups by number of group from 1.
+	// Styles of nested groups have priority.
+	Groups map[int]tcell.Style
+}
+
+// RegexpColorize return colorize of matches of regular expressions
+// inside line text. Style is applied to runes of match.
+// Styles of next regular expressions have priority.
+func RegexpColorize(rs ...RegexpStyle) Colorize {
+	return func(words []string) (styles []*tcell.Style) {
+		line := strings.Join(words, "")
+		styles = make([]*tcell.Style, utf8.RuneCountInString(line))
+		// mark runes between bytes from and to
+		mark := func(from, to int, st tcell.Style) {
+			if from < 0 || from == to {
+				return
+			}
+			s := st
+			i := utf8.RuneCountInString(line[:from])
+			for range line[from:to] {
+				styles[i] = &s
+				i++
+			}
+		}
+		for _, r := range rs {
+			if r.Regexp == nil {
+				continue
+			}
+			groups := make([]int, 0, len(r.Groups))
+			for g := range r.Groups {
+				groups = append(groups, g)
+			}
+			sort.Ints(groups)
+			for _, index := range r.Regexp.FindAllStringSubmatchIndex(line, -1) {
+				if len(groups) == 0 {
+					mark(index[0], index[1], r.Style)
+					continue
+				}
+				for _, g := range groups {
+					if g < 1 || len(index) <= 2*g+1 {
+						continue
+					}
+					mark(index[2*g], index[2*g+1], r.Groups[g])
+				}
+			}
+		}
+		return
+	}
+}
+
 type Viewer struct {
 	ContainerVerticalFix
 	colorize  []Colorize
 	str       string
+	ansi      []ansiStyle // styles of runes from escape sequences
 	noUpdate  bool
 	data      [][]Cell
 	linePos   [][]uint // counter
 	lastWidth uint
+	lastStyle tcell.Style // text style of theme for last rendering
 	position  uint
+
+	SearchIgnoreCase bool // case-insensitive search
+	SearchRegexp     bool // search by regular expression
+
+	// Preformatted text keep whitespaces and empty lines, for example
+	// source code and logs
+	Preformatted bool
+	// TabWidth is width of tabulation in preformatted text,
+	// if zero then DefaultTabWidth is used
+	TabWidth uint
+	// NoWrap is true for scroll long lines of preformatted text
+	// horizontally instead of wrapping with WrapMarker of theme
+	NoWrap bool
+
+	WordWrap bool // wrap text by words, not preformatted text only
+	Justify  bool // justify wrapped by words text
+
+	search   viewerSearch
+	column   uint       // first visible column of not wrapped text
+	lastMode viewerMode // mode of last rendering
+
+	layout func(width uint) // rendering of data and positions instead of text
+}
+
+// DefaultTabWidth is width of tabulation in preformatted text of Viewer
+var DefaultTabWidth uint = 8
+
+// viewerMode is mode of Viewer rendering
+type viewerMode struct {
+	preformatted bool
+	tabWidth     uint
+	noWrap       bool
+	wordWrap     bool
+	justify      bool
 }
 
+// viewerSearch is state of search inside Viewer
+type viewerSearch struct {
+	query   string
+	re      *regexp.Regexp
+	matches []uint // positions of matches
+	current int    // index of current match
+	input   bool   // query is typing
+	origin  uint   // position before typing of query
+	reveal  bool   // current match must be visible in not wrapped text
+}
+
+// SetColorize set colorize of text. Styles of next colorize have
+// priority, search highlights have priority over all colorize.
 func (v *Viewer) SetColorize(colorize ...Colorize) {
 	v.colorize = colorize
 	v.noUpdate = false
 }
 
+// SetText set text of viewer. ANSI escape sequences of styles (SGR) are
+// converted to styles of text, other escape sequences are removed.
 func (v *Viewer) SetText(str string) {
-	v.str = str
+	v.str, v.ansi = parseANSI(str)
 	v.noUpdate = false
 }
 
@@ -1534,29 +3520,126 @@ func (v *Viewer) SetText(str string) {
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
 // end render.doc
 func (v *Viewer) Render(width uint, dr Drawer) (height uint) {
+	theme := v.theme()
 	defer func() {
 		v.StoreSize(width, height)
 	}()
-	if !v.noUpdate || v.lastWidth != width {
+	mode := viewerMode{
+		preformatted: v.Preformatted,
+		tabWidth:     v.TabWidth,
+		noWrap:       v.NoWrap,
+		wordWrap:     v.WordWrap,
+		justify:      v.Justify,
+	}
+	if !v.noUpdate || v.lastWidth != width || v.lastStyle != theme.TextStyle ||
+		v.lastMode != mode {
 		v.render(width)
+		if len(v.data) == 0 {
+			// empty text is drawn as empty row
+			data := make([]Cell, width+1)
+			for col := range data {
+				data[col] = Cell{S: theme.TextStyle, R: ' '}
+			}
+			v.data = [][]Cell{data}
+			v.linePos = [][]uint{make([]uint, width+1)}
+		}
 		v.noUpdate = true
 		v.lastWidth = width
+		v.lastStyle = theme.TextStyle
+		v.lastMode = mode
 	}
+	column := v.scroll(width)
 	// drawing
+	bar := v.search.input || v.search.query != ""
+	hmax := v.hmax
+	if bar && 0 < hmax {
+		hmax--
+	}
 	row := v.presentRow()
 	for ; row < len(v.data); row++ {
-		if v.addlimit && height == v.hmax {
+		if v.addlimit && height == hmax {
 			break
 		}
-		for col := range v.data[row] {
-			dr(uint(height), uint(col), v.data[row][col].S, v.data[row][col].R)
+		if !v.Preformatted || !v.NoWrap {
+			for col := range v.data[row] {
+				dr(uint(height), uint(col), v.data[row][col].S, v.data[row][col].R)
+			}
+			height++
+			continue
+		}
+		// not wrapped text
+		for col := uint(0); col <= width; col++ {
+			cell := Cell{S: theme.TextStyle, R: ' '}
+			if column+col < uint(len(v.data[row])) {
+				cell = v.data[row][column+col]
+			}
+			dr(height, col, cell.S, cell.R)
+		}
+		height++
+	}
+	if bar && (!v.addlimit || 0 < v.hmax) {
+		// search bar
+		st := theme.InputBoxStyle
+		if v.search.input {
+			st = theme.InputBoxFocusStyle
+		}
+		rs := []rune(v.searchBar())
+		for col := uint(0); col <= width; col++ {
+			r := ' '
+			if col < uint(len(rs)) {
+				r = rs[col]
+			}
+			dr(height, col, st, r)
 		}
 		height++
 	}
 	return
 }
 
-func (v Viewer) presentRow() int {
+// scroll return first visible column of not wrapped text
+func (v *Viewer) scroll(width uint) uint {
+	if !v.Preformatted || !v.NoWrap {
+		return 0
+	}
+	if v.search.reveal {
+		// show current match
+		v.search.reveal = false
+		if row := v.presentRow(); 0 <= row {
+			for col, pos := range v.linePos[row] {
+				if pos != v.position {
+					continue
+				}
+				if uint(col) < v.column || v.column+width <= uint(col) {
+					v.column = uint(col)
+				}
+				break
+			}
+		}
+	}
+	var size uint
+	for row := range v.data {
+		if size < uint(len(v.data[row])) {
+			size = uint(len(v.data[row]))
+		}
+	}
+	if size < v.column+width+1 {
+		v.column = 0
+		if width+1 < size {
+			v.column = size - width - 1
+		}
+	}
+	return v.column
+}
+
+// SetColumn set first visible column of not wrapped preformatted text
+func (v *Viewer) SetColumn(column uint) {
+	v.column = column
+}
+
+// GetColumn return first visible column of not wrapped preformatted text
+func (v *Viewer) GetColumn() uint { return v.column }
+
+func (v *Viewer) presentRow() int {
 	for row := range v.linePos {
 		for col := range v.linePos[row] {
 			if v.linePos[row][col] == v.position || v.position < v.linePos[row][col] {
@@ -1602,64 +3685,611 @@ func (v *Viewer) SetPosition(position uint) {
 }
 func (v *Viewer) GetPosition() (position uint) { return v.position }
 
-// Render ...
-// snippet render.doc
-// Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
-// end render.doc
-func (v *Viewer) render(width uint) {
-	// convert to string lines
-	v.str = strings.ReplaceAll(v.str, "\r", "")
-	v.str = strings.ReplaceAll(v.str, string(rune(160)), " ")
-	lines := strings.Split(v.str, "\n")
-	for i := range lines {
-		lines[i] = strings.TrimSpace(lines[i])
-	}
-	// constants
-	const space = rune(' ')
-	// parse one line
-	OneLine := func(line string) (
-		// return data
-		data [][]Cell,
-		linePos [][]uint,
-	) {
-		if len(line) == 0 {
-			return
+// lines return lines of text like in rendering
+func (v *Viewer) lines() []string {
+	lines, _ := v.styledLines()
+	return lines
+}
+
+// styledLines return lines like in rendering and styles of runes from
+// escape sequences. Nil style is text style of theme.
+func (v *Viewer) styledLines() (lines []string, styles [][]*tcell.Style) {
+	theme := v.theme()
+	cache := map[ansiStyle]*tcell.Style{}
+	var (
+		runes []rune
+		ss    []*tcell.Style
+	)
+	add := func() {
+		for 0 < len(runes) && unicode.IsSpace(runes[len(runes)-1]) {
+			runes, ss = runes[:len(runes)-1], ss[:len(ss)-1]
 		}
-		runes := []rune(line)
-		// split by words
-		ws := make([]word, 0, len(runes))
-		ws = append(ws, word{S: &TextStyle, R: []rune{runes[0]}})
-		for ilet := 1; ilet < len(runes); ilet++ {
-			if !unicode.IsLetter(runes[ilet]) {
-				ws = append(ws, word{S: &TextStyle, R: []rune{runes[ilet]}})
-				continue
+		if v.Preformatted {
+			runes, ss = expandTabRunes(runes, ss, v.TabWidth)
+		} else {
+			for 0 < len(runes) && unicode.IsSpace(runes[0]) {
+				runes, ss = runes[1:], ss[1:]
 			}
-			if unicode.IsLetter(runes[ilet-1]) {
-				ws[len(ws)-1].R = append(ws[len(ws)-1].R, runes[ilet])
-				continue
+		}
+		lines = append(lines, string(runes))
+		styles = append(styles, ss)
+		runes, ss = nil, nil
+	}
+	var index int
+	for _, r := range v.str {
+		var st *tcell.Style
+		if index < len(v.ansi) && v.ansi[index] != (ansiStyle{}) {
+			a := v.ansi[index]
+			if st = cache[a]; st == nil {
+				s := a.style(theme.TextStyle)
+				st = &s
+				cache[a] = st
 			}
-			ws = append(ws, word{S: &TextStyle, R: []rune{runes[ilet]}})
 		}
-		// create list of words
-		var words []string
-		for n := range ws {
-			words = append(words, string(ws[n].R))
+		index++
+		switch r {
+		case '\r':
+			continue
+		case '\n':
+			add()
+			continue
+		case rune(160):
+			r = ' '
 		}
-		// add colors
-		for i := range v.colorize {
-			if v.colorize[i] == nil {
-				continue
-			}
-			styles := v.colorize[i](words)
-			if len(styles) != len(words) {
-				return
+		runes = append(runes, r)
+		ss = append(ss, st)
+	}
+	add()
+	return
+}
+
+// expandTabs replace tabulations by spaces up to next tab stop
+func expandTabs(line string, width uint) string {
+	if !strings.ContainsRune(line, '\t') {
+		return line
+	}
+	runes, _ := expandTabRunes([]rune(line), nil, width)
+	return string(runes)
+}
+
+// expandTabRunes replace tabulations by spaces up to next tab stop.
+// Spaces have style of tabulation.
+func expandTabRunes(line []rune, styles []*tcell.Style, width uint) (
+	rs []rune, ss []*tcell.Style,
+) {
+	if width == 0 {
+		width = DefaultTabWidth
+	}
+	if width == 0 {
+		width = 1
+	}
+	var col uint
+	for i, r := range line {
+		var st *tcell.Style
+		if i < len(styles) {
+			st = styles[i]
+		}
+		if r != '\t' {
+			rs = append(rs, r)
+			ss = append(ss, st)
+			col++
+			continue
+		}
+		for {
+			rs = append(rs, ' ')
+			ss = append(ss, st)
+			col++
+			if col%width == 0 {
+				break
+			}
+		}
+	}
+	return
+}
+
+// ansiStyle is style of text from ANSI escape sequences. Default colors
+// are colors of theme.
+type ansiStyle struct {
+	fg, bg tcell.Color
+	attr   tcell.AttrMask
+}
+
+// style return base style with colors and attributes of escape sequences
+func (a ansiStyle) style(base tcell.Style) tcell.Style {
+	fg, bg, attr := base.Decompose()
+	if a.fg != tcell.ColorDefault {
+		fg = a.fg
+	}
+	if a.bg != tcell.ColorDefault {
+		bg = a.bg
+	}
+	return base.Foreground(fg).Background(bg).Attributes(attr | a.attr)
+}
+
+// sgr change style by parameters of SGR escape sequence
+func (a *ansiStyle) sgr(params []int) {
+	if len(params) == 0 {
+		params = []int{0}
+	}
+	// color return extended color and amount of used parameters
+	color := func(ps []int) (c tcell.Color, n int) {
+		switch {
+		case 2 <= len(ps) && ps[0] == 5:
+			return tcell.PaletteColor(ps[1] & 0xFF), 2
+		case 4 <= len(ps) && ps[0] == 2:
+			return tcell.NewRGBColor(int32(ps[1]&0xFF), int32(ps[2]&0xFF), int32(ps[3]&0xFF)), 4
+		}
+		return tcell.ColorDefault, len(ps)
+	}
+	attrs := map[int]tcell.AttrMask{
+		1: tcell.AttrBold,
+		2: tcell.AttrDim,
+		3: tcell.AttrItalic,
+		4: tcell.AttrUnderline,
+		5: tcell.AttrBlink,
+		7: tcell.AttrReverse,
+		9: tcell.AttrStrikeThrough,
+	}
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		switch {
+		case p == 0:
+			*a = ansiStyle{}
+		case attrs[p] != 0:
+			a.attr |= attrs[p]
+		case p == 22:
+			a.attr &^= tcell.AttrBold | tcell.AttrDim
+		case 23 <= p && p <= 29 && attrs[p-20] != 0:
+			a.attr &^= attrs[p-20]
+		case 30 <= p && p <= 37:
+			a.fg = tcell.PaletteColor(p - 30)
+		case 90 <= p && p <= 97:
+			a.fg = tcell.PaletteColor(p - 90 + 8)
+		case 40 <= p && p <= 47:
+			a.bg = tcell.PaletteColor(p - 40)
+		case 100 <= p && p <= 107:
+			a.bg = tcell.PaletteColor(p - 100 + 8)
+		case p == 39:
+			a.fg = tcell.ColorDefault
+		case p == 49:
+			a.bg = tcell.ColorDefault
+		case p == 38 || p == 48:
+			c, n := color(params[i+1:])
+			i += n
+			if p == 38 {
+				a.fg = c
+			} else {
+				a.bg = c
+			}
+		}
+	}
+}
+
+// parseANSI return text without escape sequences and styles of runes.
+// Styles are nil if text have not escape sequences.
+func parseANSI(str string) (text string, styles []ansiStyle) {
+	const esc = '\x1b'
+	if !strings.ContainsRune(str, esc) {
+		return str, nil
+	}
+	var (
+		b     strings.Builder
+		style ansiStyle
+	)
+	runes := []rune(str)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != esc {
+			b.WriteRune(runes[i])
+			styles = append(styles, style)
+			continue
+		}
+		i++
+		if len(runes) <= i {
+			break
+		}
+		switch runes[i] {
+		case '[':
+			// control sequence
+			// parameter and intermediate bytes
+			start := i + 1
+			for i++; i < len(runes) && 0x20 <= runes[i] && runes[i] <= 0x3F; i++ {
+			}
+			if len(runes) <= i {
+				break
+			}
+			if runes[i] < 0x40 || 0x7E < runes[i] {
+				// not valid sequence, keep next text
+				i--
+				continue
+			}
+			if runes[i] != 'm' {
+				continue
+			}
+			var params []int
+			ps := strings.ReplaceAll(string(runes[start:i]), ":", ";")
+			for _, p := range strings.Split(ps, ";") {
+				value, err := strconv.Atoi(p)
+				switch {
+				case p == "":
+					value = 0
+				case err != nil:
+					value = -1
+				}
+				params = append(params, value)
+			}
+			style.sgr(params)
+		case ']':
+			// operating system command ended by BEL or ST
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\a' {
+					break
+				}
+				if runes[i] == esc && i+1 < len(runes) && runes[i+1] == '\\' {
+					i++
+					break
+				}
+			}
+		case '(', ')', '*', '+':
+			// character set
+			i++
+		}
+	}
+	return b.String(), styles
+}
+
+// Search find all matches of query, highlight them and move position
+// to first match after present position. Return amount of matches.
+// Empty query clear search.
+func (v *Viewer) Search(query string) (matches int, err error) {
+	v.noUpdate = false
+	v.search.query = query
+	v.search.re = nil
+	v.search.matches = nil
+	v.search.current = 0
+	if query == "" {
+		return
+	}
+	if !v.SearchRegexp {
+		query = regexp.QuoteMeta(query)
+	}
+	if v.SearchIgnoreCase {
+		query = "(?i)" + query
+	}
+	re, err := regexp.Compile(query)
+	if err != nil {
+		return
+	}
+	v.search.re = re
+	// positions of matches
+	var base uint
+	for _, line := range v.lines() {
+		for _, index := range re.FindAllStringIndex(line, -1) {
+			if index[0] == index[1] {
+				continue
+			}
+			pos := base + uint(utf8.RuneCountInString(line[:index[0]]))
+			v.search.matches = append(v.search.matches, pos)
+		}
+		base += uint(utf8.RuneCountInString(line))
+		if v.Preformatted {
+			base++ // new line
+		}
+	}
+	// first match after present position
+	for i, pos := range v.search.matches {
+		if v.position <= pos {
+			v.search.current = i
+			break
+		}
+	}
+	v.searchMove(0)
+	return len(v.search.matches), nil
+}
+
+// SearchNext move position to next match
+func (v *Viewer) SearchNext() { v.searchMove(1) }
+
+// SearchPrev move position to previous match
+func (v *Viewer) SearchPrev() { v.searchMove(-1) }
+
+// SearchStatus return number of current match from 1 and amount of
+// matches. Number is zero if matches are not found.
+func (v *Viewer) SearchStatus() (current, total int) {
+	total = len(v.search.matches)
+	if total == 0 {
+		return
+	}
+	return v.search.current + 1, total
+}
+
+// searchMove change current match by step and move position to it
+func (v *Viewer) searchMove(step int) {
+	size := len(v.search.matches)
+	if size == 0 {
+		return
+	}
+	v.search.current = (v.search.current + step + size) % size
+	v.position = v.search.matches[v.search.current]
+	v.search.reveal = true
+}
+
+// searchColorize return colorize of runes of matches
+func (v *Viewer) searchColorize() Colorize {
+	theme := v.theme()
+	re := v.search.re
+	st := theme.SearchStyle
+	return func(words []string) (styles []*tcell.Style) {
+		line := strings.Join(words, "")
+		styles = make([]*tcell.Style, utf8.RuneCountInString(line))
+		for _, index := range re.FindAllStringIndex(line, -1) {
+			i := utf8.RuneCountInString(line[:index[0]])
+			for range line[index[0]:index[1]] {
+				styles[i] = &st
+				i++
+			}
+		}
+		return
+	}
+}
+
+// searchBar return text of search bar
+func (v *Viewer) searchBar() string {
+	current, total := v.SearchStatus()
+	return fmt.Sprintf("/%s [%d/%d]", v.search.query, current, total)
+}
+
+// Event ...
+// snippet event.doc
+// For create action for widget
+// end event.doc
+func (v *Viewer) Event(ev tcell.Event) {
+	v.container.Event(ev)
+	if !v.focus {
+		return
+	}
+	key, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return
+	}
+	if v.search.input {
+		// typing of query
+		query := []rune(v.search.query)
+		switch key.Key() {
+		case tcell.KeyEnter:
+			v.search.input = false
+			v.noUpdate = false
+			return
+		case tcell.KeyEscape:
+			v.search.input = false
+			v.position = v.search.origin
+			query = nil
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if 0 < len(query) {
+				query = query[:len(query)-1]
+			}
+		case tcell.KeyRune:
+			query = append(query, key.Rune())
+		default:
+			return
+		}
+		v.Search(string(query))
+		return
+	}
+	switch {
+	case key.Key() == tcell.KeyCtrlF,
+		key.Key() == tcell.KeyRune && key.Rune() == '/':
+		v.search.input = true
+		v.search.origin = v.position
+		v.Search("")
+	case key.Key() == tcell.KeyRune && key.Rune() == 'n':
+		v.SearchNext()
+	case key.Key() == tcell.KeyRune && key.Rune() == 'N':
+		v.SearchPrev()
+	case key.Key() == tcell.KeyEscape:
+		v.Search("")
+	}
+	if v.Preformatted && v.NoWrap {
+		// horizontal scrolling
+		switch key.Key() {
+		case tcell.KeyLeft:
+			if 0 < v.column {
+				v.column--
+			}
+		case tcell.KeyRight:
+			v.column++
+		case tcell.KeyHome:
+			v.column = 0
+		}
+	}
+}
+
+// AcceptFocus return true if widget may be focused by keyboard
+func (v *Viewer) AcceptFocus() bool { return true }
+
+// AcceptEscape return true if key Escape is used for search
+func (v *Viewer) AcceptEscape() bool {
+	return v.focus && (v.search.input || v.search.query != "")
+}
+
+// words return words of line with styles of escape sequences, colorize
+// and search
+func (v *Viewer) words(line string, styles []*tcell.Style, search Colorize) (ws []word) {
+	theme := v.theme()
+	if len(line) == 0 {
+		return nil
+	}
+	runes := []rune(line)
+	// style of rune from escape sequences
+	style := func(i int) *tcell.Style {
+		if i < len(styles) && styles[i] != nil {
+			return styles[i]
+		}
+		return &theme.TextStyle
+	}
+	// split by words
+	ws = make([]word, 0, len(runes))
+	ws = append(ws, word{S: style(0), R: []rune{runes[0]}})
+	for ilet := 1; ilet < len(runes); ilet++ {
+		if !unicode.IsLetter(runes[ilet]) {
+			ws = append(ws, word{S: style(ilet), R: []rune{runes[ilet]}})
+			continue
+		}
+		if unicode.IsLetter(runes[ilet-1]) && style(ilet) == style(ilet-1) {
+			ws[len(ws)-1].R = append(ws[len(ws)-1].R, runes[ilet])
+			continue
+		}
+		ws = append(ws, word{S: style(ilet), R: []rune{runes[ilet]}})
+	}
+	// styles of runes
+	rs := make([]*tcell.Style, len(runes))
+	for i := range rs {
+		rs[i] = style(i)
+	}
+	// create list of words
+	words := make([]string, len(ws))
+	// add colors, search highlights on top of colorize
+	colorize := append(append([]Colorize(nil), v.colorize...), search)
+	for _, c := range colorize {
+		if c == nil {
+			continue
+		}
+		// colorize may change words
+		for n := range ws {
+			words[n] = string(ws[n].R)
+		}
+		styles := c(words)
+		switch len(styles) {
+		case len(runes):
+			for i := range styles {
+				if styles[i] != nil {
+					rs[i] = styles[i]
+				}
 			}
+		case len(words):
+			var i int
 			for n := range ws {
-				if styles[n] == nil {
-					continue
+				for range ws[n].R {
+					if styles[n] != nil {
+						rs[i] = styles[n]
+					}
+					i++
+				}
+			}
+		default:
+			return nil
+		}
+	}
+	// split words by styles of runes
+	parts := make([]word, 0, len(ws))
+	var i int
+	for n := range ws {
+		for k, r := range ws[n].R {
+			if k == 0 || rs[i] != rs[i-1] {
+				parts = append(parts, word{S: rs[i]})
+			}
+			parts[len(parts)-1].R = append(parts[len(parts)-1].R, r)
+			i++
+		}
+	}
+	return parts
+}
+
+// renderPreformatted render lines without changes of whitespaces.
+// Positions of text include new lines.
+func (v *Viewer) renderPreformatted(width uint, lines []string, styles [][]*tcell.Style,
+	search Colorize) {
+	theme := v.theme()
+	v.data = nil
+	v.linePos = nil
+	if width == 0 {
+		return
+	}
+	step := width
+	if !v.NoWrap && 1 < width {
+		step-- // place for wrap marker
+	}
+	var base uint
+	for i, line := range lines {
+		var cells []Cell
+		for _, w := range v.words(line, styles[i], search) {
+			for _, r := range w.R {
+				cells = append(cells, Cell{S: *w.S, R: r})
+			}
+		}
+		size := uint(len(cells))
+		// ranges of cells for each row
+		parts := [][2]uint{{0, size}}
+		if !v.NoWrap && step < size {
+			parts = nil
+			for from := uint(0); from < size; from += step {
+				to := from + step
+				if size < to {
+					to = size
+				}
+				parts = append(parts, [2]uint{from, to})
+			}
+		}
+		for i, part := range parts {
+			last := i == len(parts)-1
+			n := width + 1
+			if n < part[1]-part[0] {
+				n = part[1] - part[0]
+			}
+			data := make([]Cell, n)
+			linePos := make([]uint, n)
+			for col := range data {
+				data[col] = Cell{S: theme.TextStyle, R: ' '}
+				pos := part[0] + uint(col)
+				switch {
+				case last && size < pos:
+					pos = size
+				case !last && part[1] <= pos:
+					pos = part[1] - 1
 				}
-				ws[n].S = styles[n]
+				linePos[col] = base + pos
+			}
+			copy(data, cells[part[0]:part[1]])
+			if !last {
+				data[step] = Cell{S: theme.TextStyle, R: theme.WrapMarker}
 			}
+			v.data = append(v.data, data)
+			v.linePos = append(v.linePos, linePos)
+		}
+		base += size + 1
+	}
+}
+
+// Render ...
+// snippet render.doc
+// Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
+// end render.doc
+func (v *Viewer) render(width uint) {
+	theme := v.theme()
+	if v.layout != nil {
+		v.layout(width)
+		return
+	}
+	// convert to string lines
+	lines, styles := v.styledLines()
+	var search Colorize
+	if v.search.re != nil {
+		search = v.searchColorize()
+	}
+	if v.Preformatted {
+		v.renderPreformatted(width, lines, styles, search)
+		return
+	}
+	// constants
+	const space = rune(' ')
+	// parse one line
+	OneLine := func(line string, styles []*tcell.Style) (
+		// return data
+		data [][]Cell,
+		linePos [][]uint,
+	) {
+		ws := v.words(line, styles, search)
+		if len(ws) == 0 {
+			return
 		}
 
 		// drawing to image
@@ -1668,6 +4298,24 @@ func (v *Viewer) render(width uint) {
 		var counter uint
 		render := func(width uint, dr Drawer) (height uint) {
 			counter = 0
+			if v.WordWrap {
+				var runes []rune
+				var styles []*tcell.Style
+				for k := range ws {
+					for ir := range ws[k].R {
+						runes = append(runes, ws[k].R[ir])
+						styles = append(styles, ws[k].S)
+					}
+				}
+				ps, rows := wrapWords(runes, width, v.Justify)
+				for i := range runes {
+					counter++
+					if !ps[i].hide {
+						dr(ps[i].row, ps[i].col, *styles[i], runes[i])
+					}
+				}
+				return rows + 1
+			}
 			pos := uint(0)
 			for k := range ws {
 				for ir := range ws[k].R {
@@ -1695,7 +4343,7 @@ func (v *Viewer) render(width uint) {
 			for i := 0; i < int(height); i++ {
 				data[i] = make([]Cell, width+1)
 				for k := range data[i] {
-					data[i][k] = Cell{S: TextStyle, R: space}
+					data[i][k] = Cell{S: theme.TextStyle, R: space}
 				}
 			}
 			dr := func(row, col uint, s tcell.Style, r rune) {
@@ -1731,7 +4379,7 @@ func (v *Viewer) render(width uint) {
 	for i := range lines {
 		wg.Add(1)
 		go func(i int) {
-			datas[i], linePos[i] = OneLine(lines[i])
+			datas[i], linePos[i] = OneLine(lines[i], styles[i])
 			wg.Done()
 		}(i)
 	}
@@ -1751,7 +4399,7 @@ func (v *Viewer) render(width uint) {
 			}
 			row := make([]Cell, len(v.data[0]))
 			for k := range row {
-				row[k] = Cell{S: TextStyle, R: space}
+				row[k] = Cell{S: theme.TextStyle, R: space}
 			}
 			v.data = append(v.data, row)
 		}
@@ -1759,6 +4407,10 @@ func (v *Viewer) render(width uint) {
 		v.linePos = append(v.linePos, linePos[i]...)
 	}
 
+	if v.WordWrap {
+		// hidden spaces at the end of rows are not checked
+		return
+	}
 	for row := 0; row < len(v.linePos); row++ {
 		for col := 0; col < len(v.linePos[row]); col++ {
 			if row*int(width)+col < int(v.linePos[row][col]) {
@@ -1773,74 +4425,645 @@ func (v *Viewer) render(width uint) {
 
 ///////////////////////////////////////////////////////////////////////////////
 
-var _ Widget = (*Image)(nil)
+var _ Widget = (*Markdown)(nil)
 
-type Image struct {
-	ContainerVerticalFix
-	data [][]Cell
-}
+// Markdown is Viewer of text in markdown format. Supported:
+//
+//	# Headings
+//	**bold**, *italic*, `inline code`
+//	* bullet and 1. numbered lists
+//	```
+//	fenced code blocks
+//	```
+//	> block quotes
+//	---
+//	[links](url)
+//
+// Keys: Left and Right select link, Enter activate selected link,
+// Up and Down scroll text, PgUp and PgDn change page.
+type Markdown struct {
+	Viewer
 
-func (img *Image) SetImage(data [][]Cell) {
-	img.data = data
-}
+	// OnLink is called at activation of link by mouse or key Enter
+	OnLink func(url string)
 
-// Render ...
-// snippet render.doc
-// Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
-// end render.doc
-func (img *Image) Render(width uint, dr Drawer) (height uint) {
-	defer func() {
-		img.StoreSize(width, height)
-	}()
-	for row := range img.data {
-		for col := range img.data[row] {
-			dr(uint(row), uint(col), img.data[row][col].S, img.data[row][col].R)
-		}
-	}
-	height = uint(len(img.data))
-	return
+	lines    []mdLine
+	links    []string // url of links
+	selected int      // number of selected link from 1, or zero
+	cells    [][]int  // index of link for each cell of rows, or -1
 }
 
-///////////////////////////////////////////////////////////////////////////////
+// mdKind is kind of markdown line
+type mdKind uint8
 
-// Frame examples
-//
-//	+- Header ---------+
-//	|      Root        |
-//	+------------------+
-type Frame struct {
-	ContainerVerticalFix
-	rootable
+const (
+	mdText    mdKind = iota // paragraph or list item
+	mdHeading               // heading
+	mdQuote                 // block quote
+	mdFenced                // line of fenced code block
+	mdRule                  // horizontal rule
+)
 
-	Header       Widget
-	offsetHeader Offset
-	offsetRoot   Offset
-	NoBorder     bool
+// mdAttr is inline style of rune
+type mdAttr uint8
 
-	cleaned []bool
+const (
+	mdBold   mdAttr = 1 << iota // **bold**
+	mdItalic                    // *italic*
+	mdCode                      // `code`
+	mdLink                      // [link](url)
+)
+
+// mdLine is line of text without markdown markup
+type mdLine struct {
+	kind   mdKind
+	runes  []rune
+	attrs  []mdAttr
+	links  []int  // index of link for each rune, or -1
+	indent int    // indent of list item
+	marker string // marker of numbered list item
+	bullet bool   // item of bullet list
 }
 
-// SetHeight ...
-// snippet setheight.doc
-// Store maximal height of widget.
-// end setheight.doc
-func (f *Frame) SetHeight(hmax uint) {
-	f.ContainerVerticalFix.SetHeight(hmax)
-	if f.root != nil {
-		if _, ok := f.root.(VerticalFix); ok {
-			f.root.(VerticalFix).SetHeight(hmax)
-		}
+var (
+	mdFence   = regexp.MustCompile("^(```|~~~)")
+	mdHeader  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdRuler   = regexp.MustCompile(`^((\*\s*){3,}|(-\s*){3,}|(_\s*){3,})$`)
+	mdQuoted  = regexp.MustCompile(`^>\s?(.*)$`)
+	mdItem    = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdEscaped = "\\`*_[]()#+-.!>"
+)
+
+// SetText set text in markdown format
+func (m *Markdown) SetText(str string) {
+	m.lines, m.links = parseMarkdown(str)
+	m.selected = 0
+	var plain []string
+	for i := range m.lines {
+		plain = append(plain, string(m.lines[i].runes))
 	}
+	// text of viewer is used for search
+	m.Viewer.SetText(strings.Join(plain, "\n"))
 }
 
-// Focus ...
-// snippet focus.doc
-// For changing focus-state of widget
-// end focus.doc
-func (f *Frame) Focus(focus bool) {
-	if !focus {
-		if w := f.Header; w != nil {
-			w.Focus(focus)
+// parseMarkdown return lines of text without markup and url of links
+func parseMarkdown(str string) (lines []mdLine, links []string) {
+	var (
+		para  []string // lines of paragraph
+		line  mdLine   // paragraph properties
+		fence string   // fence of code block
+	)
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		line.runes, line.attrs, line.links = parseInline(strings.Join(para, " "), &links)
+		lines = append(lines, line)
+		para = nil
+	}
+	// separate adds empty line between blocks
+	separate := func(next mdLine) {
+		flush()
+		if len(lines) == 0 {
+			return
+		}
+		last := lines[len(lines)-1]
+		if last.kind == mdText && len(last.runes) == 0 && last.marker == "" && !last.bullet {
+			return
+		}
+		if (last.bullet || last.marker != "") && (next.bullet || next.marker != "") {
+			// items of list
+			return
+		}
+		if last.kind == mdFenced && next.kind == mdFenced {
+			return
+		}
+		lines = append(lines, mdLine{})
+	}
+	str = strings.ReplaceAll(str, "\r", "")
+	for _, src := range strings.Split(str, "\n") {
+		trimmed := strings.TrimSpace(src)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+				continue
+			}
+			src = expandTabs(strings.TrimRightFunc(src, unicode.IsSpace), DefaultTabWidth)
+			line := mdLine{kind: mdFenced, runes: []rune(src)}
+			line.attrs = make([]mdAttr, len(line.runes))
+			line.links = make([]int, len(line.runes))
+			for i := range line.links {
+				line.links[i] = -1
+			}
+			lines = append(lines, line)
+			continue
+		}
+		switch {
+		case trimmed == "":
+			flush()
+		case mdFence.MatchString(trimmed):
+			separate(mdLine{kind: mdFenced})
+			fence = trimmed[:3]
+		case mdRuler.MatchString(trimmed):
+			separate(mdLine{kind: mdRule})
+			lines = append(lines, mdLine{kind: mdRule})
+		case mdHeader.MatchString(trimmed):
+			flush()
+			line = mdLine{kind: mdHeading}
+			separate(line)
+			para = []string{mdHeader.FindStringSubmatch(trimmed)[2]}
+			flush()
+		case mdQuoted.MatchString(trimmed):
+			text := mdQuoted.FindStringSubmatch(trimmed)[1]
+			if len(para) == 0 || line.kind != mdQuote {
+				flush()
+				line = mdLine{kind: mdQuote}
+				separate(line)
+			}
+			if strings.TrimSpace(text) == "" {
+				flush()
+				continue
+			}
+			para = append(para, text)
+		case mdItem.MatchString(src):
+			flush()
+			sub := mdItem.FindStringSubmatch(src)
+			line = mdLine{indent: len(expandTabs(sub[1], DefaultTabWidth)) / 2 * 2}
+			if unicode.IsDigit([]rune(sub[2])[0]) {
+				line.marker = sub[2]
+			} else {
+				line.bullet = true
+			}
+			separate(line)
+			para = []string{sub[3]}
+		default:
+			if len(para) == 0 {
+				line = mdLine{}
+				separate(line)
+			}
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+	return
+}
+
+// parseInline return runes of text without inline markup, styles and
+// links of runes. Url of links are added to links.
+func parseInline(text string, links *[]string) (rs []rune, attrs []mdAttr, ls []int) {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\t", " "))
+	runes := []rune(text)
+	var attr mdAttr
+	link := -1
+	add := func(r rune, a mdAttr) {
+		rs = append(rs, r)
+		attrs = append(attrs, a)
+		ls = append(ls, link)
+	}
+	// closed return true if marker is found after position
+	closed := func(pos int, marker string) bool {
+		return strings.Contains(string(runes[pos:]), marker)
+	}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes) && strings.ContainsRune(mdEscaped, runes[i+1]):
+			i++
+			add(runes[i], attr)
+		case r == '`' && closed(i+1, "`"):
+			for i++; runes[i] != '`'; i++ {
+				add(runes[i], attr|mdCode)
+			}
+		case (r == '*' || r == '_') && i+1 < len(runes) && runes[i+1] == r:
+			marker := string([]rune{r, r})
+			if attr&mdBold == 0 && !closed(i+2, marker) {
+				add(r, attr)
+				continue
+			}
+			attr ^= mdBold
+			i++
+		case r == '*' || r == '_':
+			opening := attr&mdItalic == 0
+			word := func(k int) bool {
+				return 0 <= k && k < len(runes) &&
+					(unicode.IsLetter(runes[k]) || unicode.IsDigit(runes[k]))
+			}
+			switch {
+			case opening && (!closed(i+1, string(r)) || i+1 == len(runes) ||
+				unicode.IsSpace(runes[i+1])):
+				add(r, attr)
+			case r == '_' && ((opening && word(i-1)) || (!opening && word(i+1))):
+				// snake_case
+				add(r, attr)
+			default:
+				attr ^= mdItalic
+			}
+		case r == '[' && link < 0:
+			// text of link is ended by matching bracket before url
+			end, depth := -1, 0
+			for k := i + 1; k < len(runes) && end < 0; k++ {
+				switch runes[k] {
+				case '[':
+					depth++
+				case ']':
+					if depth == 0 {
+						end = k
+					}
+					depth--
+				}
+			}
+			if end < 0 || len(runes) <= end+1 || runes[end+1] != '(' {
+				add(r, attr)
+				continue
+			}
+			url := end + 2
+			for url < len(runes) && runes[url] != ')' {
+				url++
+			}
+			if len(runes) <= url {
+				add(r, attr)
+				continue
+			}
+			*links = append(*links, string(runes[end+2:url]))
+			link = len(*links) - 1
+			for _, lr := range runes[i+1 : end] {
+				add(lr, attr|mdLink)
+			}
+			link = -1
+			i = url
+		default:
+			add(r, attr)
+		}
+	}
+	// trailing spaces
+	for 0 < len(rs) && unicode.IsSpace(rs[len(rs)-1]) {
+		rs, attrs, ls = rs[:len(rs)-1], attrs[:len(attrs)-1], ls[:len(ls)-1]
+	}
+	return
+}
+
+// style return style of rune
+func (m *Markdown) style(line mdLine, i int, found []bool) (st tcell.Style) {
+	theme := m.theme()
+	switch line.kind {
+	case mdHeading:
+		st = theme.HeadingStyle
+	case mdQuote:
+		st = theme.QuoteStyle
+	case mdFenced:
+		st = theme.CodeStyle
+	default:
+		st = theme.TextStyle
+	}
+	if i < 0 {
+		return
+	}
+	a := line.attrs[i]
+	if a&mdCode != 0 {
+		st = theme.CodeStyle
+	}
+	if a&mdLink != 0 {
+		st = theme.LinkStyle
+		if line.links[i] == m.selected-1 {
+			st = theme.LinkFocusStyle
+		}
+	}
+	if a&mdBold != 0 {
+		st = st.Bold(true)
+	}
+	if a&mdItalic != 0 {
+		st = st.Italic(true)
+	}
+	if found[i] {
+		st = theme.SearchStyle
+	}
+	return
+}
+
+// layout render lines in rows of viewer. Positions of text are
+// positions of runes without markup with new lines.
+func (m *Markdown) layout(width uint) {
+	theme := m.theme()
+	v := &m.Viewer
+	v.data = nil
+	v.linePos = nil
+	m.cells = nil
+	if width == 0 {
+		return
+	}
+	var base uint
+	for _, line := range m.lines {
+		// search matches
+		found := make([]bool, len(line.runes))
+		if re := v.search.re; re != nil {
+			text := string(line.runes)
+			for _, index := range re.FindAllStringIndex(text, -1) {
+				from := utf8.RuneCountInString(text[:index[0]])
+				to := from + utf8.RuneCountInString(text[index[0]:index[1]])
+				for k := from; k < to; k++ {
+					found[k] = true
+				}
+			}
+		}
+		// prefix of rows
+		var first, next []rune
+		switch {
+		case line.kind == mdQuote:
+			first = []rune{theme.LineVerticalUnfocus, ' '}
+			next = first
+		case line.bullet:
+			first = []rune(strings.Repeat(" ", line.indent) + string(theme.Bullet) + " ")
+		case line.marker != "":
+			first = []rune(strings.Repeat(" ", line.indent) + line.marker + " ")
+		}
+		if next == nil {
+			next = []rune(strings.Repeat(" ", len(first)))
+		}
+		// positions of runes
+		var (
+			ps   []wrapPosition
+			rows uint
+		)
+		switch line.kind {
+		case mdFenced:
+			step := width
+			if 1 < width {
+				step-- // place for wrap marker
+			}
+			ps = make([]wrapPosition, len(line.runes))
+			for i := range ps {
+				ps[i] = wrapPosition{row: uint(i) / step, col: uint(i) % step}
+			}
+			rows = (uint(len(ps)) + step - 1) / step
+		case mdRule:
+		default:
+			size := uint(1)
+			if uint(len(first)) < width {
+				size = width - uint(len(first))
+			}
+			ps, rows = wrapWords(line.runes, size, false)
+			for i := range ps {
+				ps[i].col += uint(len(first))
+			}
+		}
+		if rows == 0 {
+			rows = 1
+		}
+		// rows
+		for row := uint(0); row < rows; row++ {
+			data := make([]Cell, width+1)
+			linePos := make([]int, width+1)
+			links := make([]int, width+1)
+			for col := range data {
+				data[col] = Cell{S: m.style(line, -1, found), R: ' '}
+				if line.kind != mdFenced || width <= uint(col) {
+					data[col].S = theme.TextStyle
+				}
+				linePos[col] = -1
+				links[col] = -1
+			}
+			prefix := next
+			if row == 0 {
+				prefix = first
+			}
+			for col, r := range prefix {
+				if col < len(data) {
+					data[col] = Cell{S: theme.TextStyle, R: r}
+				}
+			}
+			if line.kind == mdRule {
+				for col := uint(0); col < width; col++ {
+					data[col] = Cell{S: theme.TextStyle, R: theme.LineHorizontalUnfocus}
+				}
+			}
+			if line.kind == mdFenced && row+1 < rows {
+				data[width-1] = Cell{S: theme.CodeStyle, R: theme.WrapMarker}
+			}
+			start := -1 // first rune of row
+			for i := range ps {
+				if ps[i].row != row || ps[i].hide || width <= ps[i].col {
+					continue
+				}
+				if start < 0 {
+					start = i
+				}
+				data[ps[i].col] = Cell{S: m.style(line, i, found), R: line.runes[i]}
+				linePos[ps[i].col] = i
+				links[ps[i].col] = line.links[i]
+			}
+			// positions of cells without runes
+			prev := len(line.runes)
+			if 0 <= start {
+				prev = start
+			}
+			last := 0
+			for col := range linePos {
+				if linePos[col] < 0 {
+					linePos[col] = prev
+					continue
+				}
+				prev = linePos[col]
+				last = col
+			}
+			if row+1 == rows {
+				for col := last + 1; col < len(linePos); col++ {
+					linePos[col] = len(line.runes)
+				}
+			}
+			pos := make([]uint, len(linePos))
+			for col := range pos {
+				pos[col] = base + uint(linePos[col])
+			}
+			v.data = append(v.data, data)
+			v.linePos = append(v.linePos, pos)
+			m.cells = append(m.cells, links)
+		}
+		base += uint(len(line.runes)) + 1
+	}
+}
+
+// Render ...
+// snippet render.doc
+// Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
+// end render.doc
+func (m *Markdown) Render(width uint, dr Drawer) (height uint) {
+	m.Preformatted = true
+	m.NoWrap = false
+	if m.Viewer.layout == nil {
+		m.Viewer.layout = m.layout
+	}
+	return m.Viewer.Render(width, dr)
+}
+
+// scroll text by amount of rows
+func (m *Markdown) scroll(rows int) {
+	if len(m.linePos) == 0 {
+		return
+	}
+	row := m.presentRow() + rows
+	if row < 0 {
+		row = 0
+	}
+	if len(m.linePos) <= row {
+		row = len(m.linePos) - 1
+	}
+	m.position = m.linePos[row][0]
+}
+
+// selectLink change selected link by step and scroll text to link
+func (m *Markdown) selectLink(step int) {
+	size := len(m.links)
+	if size == 0 {
+		return
+	}
+	m.selected = (m.selected-1+step+size)%size + 1
+	m.noUpdate = false
+	for row := range m.cells {
+		for col := range m.cells[row] {
+			if m.cells[row][col] != m.selected-1 {
+				continue
+			}
+			present := m.presentRow()
+			if row < present || (m.addlimit && present+int(m.hmax) <= row) {
+				m.position = m.linePos[row][0]
+			}
+			return
+		}
+	}
+}
+
+// activate link with index
+func (m *Markdown) activate(link int) {
+	if link < 0 || len(m.links) <= link {
+		return
+	}
+	m.selected = link + 1
+	m.noUpdate = false
+	if f := m.OnLink; f != nil {
+		f(m.links[link])
+	}
+}
+
+// Event ...
+// snippet event.doc
+// For create action for widget
+// end event.doc
+func (m *Markdown) Event(ev tcell.Event) {
+	switch ev := ev.(type) {
+	case *tcell.EventMouse:
+		button, ok := m.onFocus(ev)
+		if !ok {
+			break
+		}
+		switch {
+		case ev.Buttons()&tcell.WheelUp != 0:
+			m.scroll(-1)
+		case ev.Buttons()&tcell.WheelDown != 0:
+			m.scroll(1)
+		case button[0]:
+			col, row := ev.Position()
+			row += m.presentRow()
+			if 0 <= row && row < len(m.cells) && col < len(m.cells[row]) {
+				m.activate(m.cells[row][col])
+			}
+		}
+	case *tcell.EventKey:
+		if !m.focus || m.search.input {
+			break
+		}
+		switch ev.Key() {
+		case tcell.KeyRight:
+			m.selectLink(1)
+		case tcell.KeyLeft:
+			m.selectLink(-1)
+		case tcell.KeyEnter:
+			m.activate(m.selected - 1)
+		case tcell.KeyUp:
+			m.scroll(-1)
+		case tcell.KeyDown:
+			m.scroll(1)
+		case tcell.KeyPgUp:
+			m.PrevPage()
+		case tcell.KeyPgDn:
+			m.NextPage()
+		default:
+			m.Viewer.Event(ev)
+		}
+		return
+	}
+	m.Viewer.Event(ev)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var _ Widget = (*Image)(nil)
+
+type Image struct {
+	ContainerVerticalFix
+	data [][]Cell
+}
+
+func (img *Image) SetImage(data [][]Cell) {
+	img.data = data
+}
+
+// Render ...
+// snippet render.doc
+// Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
+// end render.doc
+func (img *Image) Render(width uint, dr Drawer) (height uint) {
+	defer func() {
+		img.StoreSize(width, height)
+	}()
+	for row := range img.data {
+		for col := range img.data[row] {
+			dr(uint(row), uint(col), img.data[row][col].S, img.data[row][col].R)
+		}
+	}
+	height = uint(len(img.data))
+	return
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// Frame examples
+//
+//	+- Header ---------+
+//	|      Root        |
+//	+------------------+
+type Frame struct {
+	ContainerVerticalFix
+	rootable
+
+	Header       Widget
+	offsetHeader Offset
+	offsetRoot   Offset
+	NoBorder     bool
+
+	cleaned []bool
+}
+
+// SetHeight ...
+// snippet setheight.doc
+// Store maximal height of widget.
+// end setheight.doc
+func (f *Frame) SetHeight(hmax uint) {
+	f.ContainerVerticalFix.SetHeight(hmax)
+	if f.root != nil {
+		if _, ok := f.root.(VerticalFix); ok {
+			f.root.(VerticalFix).SetHeight(hmax)
+		}
+	}
+}
+
+// Focus ...
+// snippet focus.doc
+// For changing focus-state of widget
+// end focus.doc
+func (f *Frame) Focus(focus bool) {
+	if !focus {
+		if w := f.Header; w != nil {
+			w.Focus(focus)
 		}
 		if w := f.root; w != nil {
 			w.Focus(focus)
@@ -1849,11 +5072,17 @@ func (f *Frame) Focus(focus bool) {
 	f.container.Focus(focus)
 }
 
+// Children return internal widgets in focus order
+func (f *Frame) Children() []Widget {
+	return []Widget{f.Header, f.root}
+}
+
 // Render ...
 // snippet render.doc
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
 // end render.doc
 func (f *Frame) Render(width uint, drg Drawer) (height uint) {
+	theme := f.theme()
 	defer func() {
 		f.StoreSize(width, height)
 	}()
@@ -1877,7 +5106,7 @@ func (f *Frame) Render(width uint, drg Drawer) (height uint) {
 				continue
 			}
 			for w := uint(0); w < width; w++ {
-				drg(r, w, TextStyle, ' ')
+				drg(r, w, theme.TextStyle, ' ')
 			}
 			f.cleaned[r] = true
 		}
@@ -1897,9 +5126,9 @@ func (f *Frame) Render(width uint, drg Drawer) (height uint) {
 		var i uint
 		for i = 0; i < width; i++ {
 			if f.focus {
-				dr(row, i, TextStyle, LineHorizontalFocus)
+				dr(row, i, theme.TextStyle, theme.LineHorizontalFocus)
 			} else {
-				dr(row, i, TextStyle, LineHorizontalUnfocus)
+				dr(row, i, theme.TextStyle, theme.LineHorizontalUnfocus)
 			}
 		}
 	}
@@ -1913,23 +5142,23 @@ func (f *Frame) Render(width uint, drg Drawer) (height uint) {
 		var r uint
 		for r = 0; r < height; r++ {
 			if f.focus {
-				dr(r, 0, TextStyle, LineVerticalFocus)
-				dr(r, width-1, TextStyle, LineVerticalFocus)
+				dr(r, 0, theme.TextStyle, theme.LineVerticalFocus)
+				dr(r, width-1, theme.TextStyle, theme.LineVerticalFocus)
 			} else {
-				dr(r, 0, TextStyle, LineVerticalUnfocus)
-				dr(r, width-1, TextStyle, LineVerticalUnfocus)
+				dr(r, 0, theme.TextStyle, theme.LineVerticalUnfocus)
+				dr(r, width-1, theme.TextStyle, theme.LineVerticalUnfocus)
 			}
 		}
 		if f.focus {
-			dr(0, 0, TextStyle, CornerLeftUpFocus)
-			dr(0, width-1, TextStyle, CornerRightUpFocus)
-			dr(height, 0, TextStyle, CornerLeftDownFocus)
-			dr(height, width-1, TextStyle, CornerRightDownFocus)
+			dr(0, 0, theme.TextStyle, theme.CornerLeftUpFocus)
+			dr(0, width-1, theme.TextStyle, theme.CornerRightUpFocus)
+			dr(height, 0, theme.TextStyle, theme.CornerLeftDownFocus)
+			dr(height, width-1, theme.TextStyle, theme.CornerRightDownFocus)
 		} else {
-			dr(0, 0, TextStyle, CornerLeftUpUnfocus)
-			dr(0, width-1, TextStyle, CornerRightUpUnfocus)
-			dr(height, 0, TextStyle, CornerLeftDownUnfocus)
-			dr(height, width-1, TextStyle, CornerRightDownUnfocus)
+			dr(0, 0, theme.TextStyle, theme.CornerLeftUpUnfocus)
+			dr(0, width-1, theme.TextStyle, theme.CornerRightUpUnfocus)
+			dr(height, 0, theme.TextStyle, theme.CornerLeftDownUnfocus)
+			dr(height, width-1, theme.TextStyle, theme.CornerRightDownUnfocus)
 		}
 		height++
 	}()
@@ -1941,16 +5170,16 @@ func (f *Frame) Render(width uint, drg Drawer) (height uint) {
 			0, maxSize,
 			0, width,
 		)
-		height = f.Header.Render(width-4, draw)
+		height = f.pass(f.Header).Render(width-4, draw)
 		// draw line
 		if !f.NoBorder {
 			wh, _ := f.Header.GetSize()
 			for i := wh; i < width-2; i++ {
 				row := uint(0)
 				if f.focus {
-					draw(row, i, TextStyle, LineHorizontalFocus)
+					draw(row, i, theme.TextStyle, theme.LineHorizontalFocus)
 				} else {
-					draw(row, i, TextStyle, LineHorizontalUnfocus)
+					draw(row, i, theme.TextStyle, theme.LineHorizontalUnfocus)
 				}
 			}
 		}
@@ -1984,7 +5213,7 @@ func (f *Frame) Render(width uint, drg Drawer) (height uint) {
 		// 	0, maxSize,
 		// 	0, width-2*f.offsetRoot.col+1,
 		// ))
-		h := f.root.Render(width-2*f.offsetRoot.col, DrawerLimit(
+		h := f.pass(f.root).Render(width-2*f.offsetRoot.col, DrawerLimit(
 			dr,
 			f.offsetRoot.row, f.offsetRoot.col,
 			0, maxSize,
@@ -2034,7 +5263,10 @@ func (f *Frame) Event(ev tcell.Event) {
 			return
 
 		case *tcell.EventKey:
-			f.Header.Event(ev)
+			if isFocused(f.Header) {
+				f.Header.Event(ev)
+				return
+			}
 		}
 	}
 	if f.root != nil {
@@ -2074,6 +5306,14 @@ func (r *radio) Focus(focus bool) {
 	}
 }
 
+// AcceptFocus return true if widget may be focused by keyboard
+func (r *radio) AcceptFocus() bool { return true }
+
+// Children return internal widgets in focus order
+func (r *radio) Children() []Widget {
+	return []Widget{r.root}
+}
+
 const banner = 4 // banner for CheckBox and radio
 
 // Render ...
@@ -2081,18 +5321,19 @@ const banner = 4 // banner for CheckBox and radio
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
 // end render.doc
 func (r *radio) Render(width uint, dr Drawer) (height uint) {
+	theme := r.theme()
 	defer func() {
 		r.StoreSize(width, height)
 	}()
 	if width < 6 {
 		return 1
 	}
-	st := ButtonStyle
+	st := theme.ButtonStyle
 	if r.choosed {
-		st = ButtonSelectStyle
+		st = theme.ButtonSelectStyle
 	}
 	if r.focus {
-		st = ButtonFocusStyle
+		st = theme.ButtonFocusStyle
 	}
 	if r.choosed {
 		PrintDrawer(0, 0, st, dr, []rune("(*)"))
@@ -2103,7 +5344,7 @@ func (r *radio) Render(width uint, dr Drawer) (height uint) {
 		if ch, ok := r.root.(*CollapsingHeader); ok {
 			ch.Open(r.choosed)
 		}
-		height = r.root.Render(width-banner, DrawerLimit(
+		height = r.pass(r.root).Render(width-banner, DrawerLimit(
 			dr,
 			0, banner,
 			0, maxSize,
@@ -2218,6 +5459,16 @@ func (rg *RadioGroup) Focus(focus bool) {
 	rg.list.Focus(focus)
 }
 
+// IsFocused return focus-state of widget
+func (rg *RadioGroup) IsFocused() bool {
+	return rg.list.focus
+}
+
+// Children return internal widgets in focus order
+func (rg *RadioGroup) Children() []Widget {
+	return rg.list.Children()
+}
+
 // Render ...
 // snippet render.doc
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
@@ -2236,7 +5487,7 @@ func (rg *RadioGroup) Render(width uint, dr Drawer) (height uint) {
 		}
 		rg.list.nodes[i].w.(*radio).choosed = false
 	}
-	height = rg.list.Render(width, dr)
+	height = rg.pass(&rg.list).Render(width, dr)
 	return
 }
 
@@ -2246,6 +5497,12 @@ func (rg *RadioGroup) Render(width uint, dr Drawer) (height uint) {
 // end event.doc
 func (rg *RadioGroup) Event(ev tcell.Event) {
 	rg.list.Event(ev)
+	if ev, ok := ev.(*tcell.EventKey); ok {
+		if ev.Key() != tcell.KeyEnter && !(ev.Key() == tcell.KeyRune && ev.Rune() == ' ') {
+			// radio position is not changed
+			return
+		}
+	}
 	if rg.list.focus {
 		// change radio position
 		last := rg.pos
@@ -2280,16 +5537,17 @@ type CheckBox struct {
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
 // end render.doc
 func (ch *CheckBox) Render(width uint, dr Drawer) (height uint) {
+	theme := ch.theme()
 	defer func() {
 		ch.width = width
 		ch.height = height
 	}()
-	st := &ButtonStyle
+	st := &theme.ButtonStyle
 	if ch.Checked {
-		st = &ButtonSelectStyle
+		st = &theme.ButtonSelectStyle
 	}
 	if ch.focus {
-		st = &ButtonFocusStyle
+		st = &theme.ButtonFocusStyle
 	}
 	if len(ch.pair[0]) == 0 || len(ch.pair[1]) == 0 {
 		// default values
@@ -2307,7 +5565,7 @@ func (ch *CheckBox) Render(width uint, dr Drawer) (height uint) {
 		PrintDrawer(0, 0, *st, dr, []rune(ch.pair[1]))
 		lenght = uint(len(ch.pair[1]))
 	}
-	dr(0, lenght, TextStyle, ' ')
+	dr(0, lenght, theme.TextStyle, ' ')
 	height = ch.Text.Render(width-lenght-1, DrawerLimit(
 		dr,
 		0, lenght+1,
@@ -2335,6 +5593,15 @@ func (ch *CheckBox) Event(ev tcell.Event) {
 	if !ch.focus {
 		return
 	}
+	if ev, ok := ev.(*tcell.EventKey); ok {
+		switch {
+		case ev.Key() == tcell.KeyEnter:
+		case ev.Key() == tcell.KeyRune && ev.Rune() == ' ':
+		default:
+			return
+		}
+		mouse[0] = true
+	}
 	if mouse[0] {
 		if !ch.ReadOnly {
 			ch.Checked = !ch.Checked
@@ -2345,30 +5612,565 @@ func (ch *CheckBox) Event(ev tcell.Event) {
 	}
 }
 
+// AcceptFocus return true if widget may be focused by keyboard
+func (ch *CheckBox) AcceptFocus() bool { return !ch.ReadOnly }
+
 ///////////////////////////////////////////////////////////////////////////////
 
 type InputBox struct {
 	Text
-}
+	Clipboard Clipboard // clipboard of widget, if nil then DefaultClipboard is used
 
-var Cursor rune = '_'
+	// HistoryDepth is maximal amount of undo steps,
+	// if zero then DefaultHistoryDepth is used
+	HistoryDepth uint
 
-// Render ...
-// snippet render.doc
+	// PasteFilter change pasted text before insertion.
+	// Text is not inserted, if filter return false.
+	// For example: PasteSingleLine, PasteRejectNewLines.
+	PasteFilter func(text string) (_ string, ok bool)
+
+	// Mask is rune for show instead of text, for example for passwords.
+	// If Mask is not zero, then text cannot be copied or cut.
+	Mask rune
+
+	// Pattern of text. Runes of pattern:
+	//
+	//	'9' - digit
+	//	'a' - letter
+	//	'*' - letter or digit
+	//
+	// Other runes of pattern are placed in text automatically,
+	// for example: "99/99/9999", "+9 (999) 999-9999".
+	Pattern string
+
+	anchor    int  // start position of selection
+	selection bool // text is selected between anchor and cursor
+
+	undo, redo []inputState // history of edits
+	lastEdit   editKind     // kind of last edit for grouping
+	lastRune   rune         // last inserted rune
+	lastTime   time.Time    // time of last edit
+}
+
+// DefaultHistoryDepth is amount of undo steps of InputBox by default
+var DefaultHistoryDepth uint = 100
+
+// HistoryGroupTime is maximal time between edits of one undo step
+var HistoryGroupTime = time.Second
+
+// inputState is state of InputBox for undo and redo
+type inputState struct {
+	text   []rune
+	cursor int
+}
+
+// editKind is kind of edit for grouping of undo steps
+type editKind uint8
+
+const (
+	editNone   editKind = iota // edit is not grouped
+	editInsert                 // insert of rune
+	editDelete                 // remove of rune
+)
+
+// Clipboard is storage of copied text
+type Clipboard interface {
+	SetText(text string)
+	GetText() string
+}
+
+// DefaultClipboard is clipboard of all InputBox widgets by default
+var DefaultClipboard Clipboard = new(MemoryClipboard)
+
+// MemoryClipboard is clipboard inside application
+type MemoryClipboard struct {
+	mutex sync.Mutex
+	text  string
+}
+
+// SetText store text in clipboard
+func (c *MemoryClipboard) SetText(text string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.text = text
+}
+
+// GetText return text from clipboard
+func (c *MemoryClipboard) GetText() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.text
+}
+
+// OSC52Clipboard is clipboard of terminal by escape sequence OSC 52.
+// Terminal clipboard is write only, so text is also stored inside
+// application for paste.
+//
+// Escape sequence is written only to Writer, which must not be shared
+// with output of screen, for example a separate descriptor of terminal
+// like "/dev/tty".
+type OSC52Clipboard struct {
+	MemoryClipboard
+	// Writer is terminal output, if nil then text is stored only inside
+	// application
+	Writer io.Writer
+}
+
+// SetText store text in terminal clipboard
+func (c *OSC52Clipboard) SetText(text string) {
+	c.MemoryClipboard.SetText(text)
+	if c.Writer == nil {
+		return
+	}
+	fmt.Fprintf(c.Writer, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+}
+
+// clipboard return clipboard of widget
+func (in *InputBox) clipboard() Clipboard {
+	if in.Clipboard == nil {
+		return DefaultClipboard
+	}
+	return in.Clipboard
+}
+
+// layout return positions of runes and end of text on screen from
+// render of TextField. Line breaks are not drawn, so position of it is
+// found by cursor placed after previous rune.
+func (in *InputBox) layout() (ps [][2]uint) {
+	if in.cwidth < 2 {
+		return nil
+	}
+	tf := &in.content.TextField
+	var drawn [][2]uint
+	var cur [2]uint
+	tf.Render(func(row, col uint, _ rune) {
+		drawn = append(drawn, [2]uint{row, col})
+	}, func(row, col uint) {
+		cur = [2]uint{row, col}
+	})
+	defer tf.CursorPosition(cur[0], cur[1])
+	// next return position after rune on position p
+	next := func(p [2]uint) (n [2]uint) {
+		tf.CursorPosition(p[0], p[1])
+		tf.CursorMoveRight()
+		tf.Render(func(_, _ uint, _ rune) {}, func(row, col uint) {
+			n = [2]uint{row, col}
+		})
+		return
+	}
+	text := in.content.GetText()
+	for i, r := range text {
+		switch {
+		case r != '\n' && 0 < len(drawn):
+			ps = append(ps, drawn[0])
+			drawn = drawn[1:]
+		case i == 0:
+			ps = append(ps, [2]uint{})
+		default:
+			ps = append(ps, next(ps[i-1]))
+		}
+	}
+	if len(ps) == 0 {
+		return [][2]uint{{}}
+	}
+	return append(ps, next(ps[len(ps)-1]))
+}
+
+// update positions of text after changes
+func (in *InputBox) update() {
+	in.content.TextField.Render(func(_, _ uint, _ rune) {}, nil)
+}
+
+// cursor return position of cursor in text
+func (in *InputBox) cursor() int {
+	in.update()
+	var pos [2]uint
+	in.content.TextField.Render(func(_, _ uint, _ rune) {}, func(row, col uint) {
+		pos = [2]uint{row, col}
+	})
+	for i, p := range in.layout() {
+		if p == pos {
+			return i
+		}
+	}
+	return 0
+}
+
+// setCursor move cursor to position in text
+func (in *InputBox) setCursor(index int) {
+	in.update()
+	ps := in.layout()
+	if index < 0 || len(ps) <= index {
+		return
+	}
+	in.content.CursorPosition(ps[index][0], ps[index][1])
+}
+
+// selected return range of selected text
+func (in *InputBox) selected() (from, to int, ok bool) {
+	if !in.selection {
+		return
+	}
+	from, to = in.anchor, in.cursor()
+	if to < from {
+		from, to = to, from
+	}
+	if size := len(in.content.GetText()); size < to {
+		to = size
+	}
+	return from, to, from < to
+}
+
+// SetText set to new widget text
+func (in *InputBox) SetText(str string) {
+	in.selection = false
+	in.Text.SetText(str)
+}
+
+// state return current state of text
+func (in *InputBox) state() inputState {
+	return inputState{
+		text:   append([]rune{}, in.content.GetText()...),
+		cursor: in.cursor(),
+	}
+}
+
+// restore state of text
+func (in *InputBox) restore(s inputState) {
+	in.SetText(string(s.text))
+	in.setCursor(s.cursor)
+	in.lastEdit = editNone
+}
+
+// edit run function `f` with change of text and store state before
+// changes in history. Edits of same kind are grouped by words and
+// by time HistoryGroupTime.
+func (in *InputBox) edit(kind editKind, r rune, f func()) {
+	if _, _, ok := in.selected(); ok {
+		// change of selected text is not grouped
+		kind = editNone
+	}
+	before := in.state()
+	f()
+	if string(before.text) == in.GetText() {
+		return
+	}
+	now := time.Now()
+	group := kind != editNone && kind == in.lastEdit &&
+		now.Sub(in.lastTime) < HistoryGroupTime
+	if kind == editInsert && unicode.IsSpace(r) && !unicode.IsSpace(in.lastRune) {
+		// new word
+		group = false
+	}
+	in.lastEdit, in.lastRune, in.lastTime = kind, r, now
+	in.redo = nil
+	if group {
+		return
+	}
+	in.undo = append(in.undo, before)
+	depth := in.HistoryDepth
+	if depth == 0 {
+		depth = DefaultHistoryDepth
+	}
+	if uint(len(in.undo)) > depth {
+		in.undo = in.undo[uint(len(in.undo))-depth:]
+	}
+}
+
+// Undo last edit of text
+func (in *InputBox) Undo() {
+	if len(in.undo) == 0 {
+		return
+	}
+	in.redo = append(in.redo, in.state())
+	in.restore(in.undo[len(in.undo)-1])
+	in.undo = in.undo[:len(in.undo)-1]
+}
+
+// Redo last undone edit of text
+func (in *InputBox) Redo() {
+	if len(in.redo) == 0 {
+		return
+	}
+	in.undo = append(in.undo, in.state())
+	in.restore(in.redo[len(in.redo)-1])
+	in.redo = in.redo[:len(in.redo)-1]
+}
+
+// SelectAll select all text
+func (in *InputBox) SelectAll() {
+	in.anchor = 0
+	in.selection = true
+	in.setCursor(len(in.content.GetText()))
+}
+
+// GetSelectedText return selected text. Masked text is not returned.
+func (in *InputBox) GetSelectedText() string {
+	from, to, ok := in.selected()
+	if !ok || in.Mask != 0 {
+		return ""
+	}
+	return string(in.content.GetText()[from:to])
+}
+
+// Copy selected text to clipboard. Masked text is not copied.
+func (in *InputBox) Copy() {
+	if in.Mask != 0 {
+		return
+	}
+	if text := in.GetSelectedText(); text != "" {
+		in.clipboard().SetText(text)
+	}
+}
+
+// Cut selected text to clipboard. Masked text is not cut.
+func (in *InputBox) Cut() {
+	if in.Mask != 0 {
+		return
+	}
+	in.Copy()
+	in.edit(editNone, 0, func() { in.deleteSelected() })
+}
+
+// Paste text from clipboard instead of selected text
+func (in *InputBox) Paste() {
+	in.paste(in.clipboard().GetText())
+}
+
+// paste text as single undo step
+func (in *InputBox) paste(text string) {
+	if f := in.PasteFilter; f != nil {
+		var ok bool
+		if text, ok = f(text); !ok {
+			return
+		}
+	}
+	in.edit(editNone, 0, func() { in.insert(text) })
+}
+
+// PasteSingleLine is PasteFilter with replacing new lines by spaces
+func PasteSingleLine(text string) (_ string, ok bool) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' {
+			return ' '
+		}
+		return r
+	}, text), true
+}
+
+// PasteRejectNewLines is PasteFilter without insertion of text with
+// new lines
+func PasteRejectNewLines(text string) (_ string, ok bool) {
+	return text, !strings.ContainsAny(text, "\r\n")
+}
+
+// insert text instead of selected text
+func (in *InputBox) insert(text string) {
+	in.deleteSelected()
+	for _, r := range text {
+		if in.Pattern != "" {
+			k := in.rawIndex(in.cursor())
+			raw := in.raw()
+			in.setRaw(append(append(append([]rune{}, raw[:k]...), r), raw[k:]...), k+1)
+			continue
+		}
+		in.content.Insert(r)
+	}
+}
+
+// backspace remove rune before cursor
+func (in *InputBox) backspace() {
+	if in.Pattern == "" {
+		in.content.KeyBackspace()
+		return
+	}
+	k := in.rawIndex(in.cursor())
+	if k == 0 {
+		return
+	}
+	raw := in.raw()
+	in.setRaw(append(append([]rune{}, raw[:k-1]...), raw[k:]...), k-1)
+}
+
+// del remove rune after cursor
+func (in *InputBox) del() {
+	if in.Pattern == "" {
+		in.content.KeyDel()
+		return
+	}
+	k := in.rawIndex(in.cursor())
+	raw := in.raw()
+	if len(raw) <= k {
+		return
+	}
+	in.setRaw(append(append([]rune{}, raw[:k]...), raw[k+1:]...), k)
+}
+
+// placeholder return true if rune of pattern is place for text rune
+func placeholder(p rune) bool {
+	return p == '9' || p == 'a' || p == '*'
+}
+
+// matchPattern return true if rune is valid for rune of pattern
+func matchPattern(p, r rune) bool {
+	switch p {
+	case '9':
+		return unicode.IsDigit(r)
+	case 'a':
+		return unicode.IsLetter(r)
+	case '*':
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	return false
+}
+
+// raw return runes of text on placeholders of pattern
+func (in *InputBox) raw() (raw []rune) {
+	pattern := []rune(in.Pattern)
+	for i, r := range in.content.GetText() {
+		if i < len(pattern) && placeholder(pattern[i]) {
+			raw = append(raw, r)
+		}
+	}
+	return
+}
+
+// rawIndex return amount of placeholders before position in text
+func (in *InputBox) rawIndex(pos int) (k int) {
+	for i, p := range []rune(in.Pattern) {
+		if pos <= i {
+			break
+		}
+		if placeholder(p) {
+			k++
+		}
+	}
+	return
+}
+
+// format return text by pattern with runes on placeholders. Literal
+// runes after last rune are added. Return false if runes are not valid.
+func (in *InputBox) format(raw []rune) (text []rune, ok bool) {
+	k := 0
+	for _, p := range []rune(in.Pattern) {
+		if !placeholder(p) {
+			if len(raw) == 0 {
+				break
+			}
+			text = append(text, p)
+			continue
+		}
+		if k == len(raw) {
+			break
+		}
+		if !matchPattern(p, raw[k]) {
+			return nil, false
+		}
+		text = append(text, raw[k])
+		k++
+	}
+	return text, k == len(raw)
+}
+
+// setRaw change text by runes on placeholders and move cursor after
+// `k` runes. Return false if runes are not valid for pattern.
+func (in *InputBox) setRaw(raw []rune, k int) bool {
+	text, ok := in.format(raw)
+	if !ok {
+		return false
+	}
+	prefix, _ := in.format(raw[:k])
+	in.content.SetText(text)
+	in.setCursor(len(prefix))
+	return true
+}
+
+// deleteSelected remove selected text and return true if text is removed
+func (in *InputBox) deleteSelected() bool {
+	from, to, ok := in.selected()
+	in.selection = false
+	if !ok {
+		return false
+	}
+	if in.Pattern != "" {
+		raw := in.raw()
+		k := in.rawIndex(from)
+		in.setRaw(append(append([]rune{}, raw[:k]...), raw[in.rawIndex(to):]...), k)
+		return true
+	}
+	text := in.content.GetText()
+	in.content.SetText(append(append([]rune{}, text[:from]...), text[to:]...))
+	in.setCursor(from)
+	return true
+}
+
+// move cursor by function and change selection if shift is pressed
+func (in *InputBox) move(f func(), shift bool) {
+	in.lastEdit = editNone
+	if !shift {
+		in.selection = false
+		f()
+		return
+	}
+	if !in.selection {
+		in.anchor = in.cursor()
+		in.selection = true
+	}
+	f()
+}
+
+// AcceptFocus return true if widget may be focused by keyboard
+func (in *InputBox) AcceptFocus() bool { return true }
+
+// Render ...
+// snippet render.doc
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
 // end render.doc
 func (in *InputBox) Render(width uint, dr Drawer) (height uint) {
+	theme := in.theme()
 	defer func() {
 		in.StoreSize(width, height)
 	}()
 	// set test property
-	st := &InputBoxStyle
+	st := &theme.InputBoxStyle
 	if in.focus {
-		st = &InputBoxFocusStyle
+		st = &theme.InputBoxFocusStyle
 	}
 	in.Text.style = st
 	in.Text.addCursor = true
-	return in.Text.Render(width, dr)
+	from, to, ok := in.selected()
+	if (!ok || !in.focus) && in.Mask == 0 {
+		return in.Text.Render(width, dr)
+	}
+	// selected text
+	cells := map[[2]uint]bool{}
+	if ok && in.focus {
+		for _, p := range in.layout()[from:to] {
+			cells[p] = true
+		}
+	}
+	// masked text, positions are calculated after change of width
+	var masked map[[2]uint]bool
+	return in.Text.Render(width, func(row, col uint, s tcell.Style, r rune) {
+		if in.Mask != 0 && masked == nil {
+			masked = map[[2]uint]bool{}
+			ps := in.layout()
+			for i, r := range in.content.GetText() {
+				if r != '\n' && i < len(ps) {
+					masked[ps[i]] = true
+				}
+			}
+		}
+		if s == *st && masked[[2]uint{row, col}] {
+			r = in.Mask
+		}
+		if s == *st && cells[[2]uint{row, col}] {
+			s = theme.InputBoxSelectStyle
+		}
+		dr(row, col, s, r)
+	})
 }
 
 // Event ...
@@ -2376,7 +6178,24 @@ func (in *InputBox) Render(width uint, dr Drawer) (height uint) {
 // For create action for widget
 // end event.doc
 func (in *InputBox) Event(ev tcell.Event) {
-	_, ok := in.onFocus(ev)
+	if ev, ok := ev.(*EventDrag); ok {
+		// selection by mouse
+		col, row := ev.Position()
+		if col < 0 {
+			col = 0
+		}
+		if int(in.width) < col {
+			col = int(in.width)
+		}
+		if row < 0 {
+			row, col = 0, 0
+		}
+		in.update()
+		in.content.CursorPosition(uint(row), uint(col))
+		in.selection = in.anchor != in.cursor()
+		return
+	}
+	button, ok := in.onFocus(ev)
 	if ok {
 		in.Focus(true)
 	}
@@ -2384,6 +6203,8 @@ func (in *InputBox) Event(ev tcell.Event) {
 		return
 	}
 	switch ev := ev.(type) {
+	case *EventPasteText:
+		in.paste(ev.Text)
 	case *tcell.EventMouse:
 		// recalculate position of mouse
 		col, row := ev.Position()
@@ -2396,33 +6217,254 @@ func (in *InputBox) Event(ev tcell.Event) {
 		if row < 0 {
 			return
 		}
+		in.update()
 		in.content.CursorPosition(uint(row), uint(col))
+		in.selection = false
+		in.lastEdit = editNone
+		if button[0] {
+			in.anchor = in.cursor()
+			CaptureMouse(in, ev)
+		}
 		return
 	case *tcell.EventKey:
+		shift := ev.Modifiers()&tcell.ModShift != 0
 		switch ev.Key() {
 		case tcell.KeyUp:
-			in.content.CursorMoveUp()
+			in.move(in.content.CursorMoveUp, shift)
 		case tcell.KeyDown:
-			in.content.CursorMoveDown()
+			in.move(in.content.CursorMoveDown, shift)
 		case tcell.KeyLeft:
-			in.content.CursorMoveLeft()
+			in.move(in.content.CursorMoveLeft, shift)
 		case tcell.KeyRight:
-			in.content.CursorMoveRight()
+			in.move(in.content.CursorMoveRight, shift)
+		case tcell.KeyCtrlA:
+			in.SelectAll()
+		case tcell.KeyCtrlC:
+			in.Copy()
+		case tcell.KeyCtrlX:
+			in.Cut()
+		case tcell.KeyCtrlV:
+			in.Paste()
+		case tcell.KeyCtrlZ:
+			in.Undo()
+		case tcell.KeyCtrlY:
+			in.Redo()
+		case tcell.KeyInsert:
+			switch {
+			case ev.Modifiers()&tcell.ModCtrl != 0:
+				in.Copy()
+			case shift:
+				in.Paste()
+			}
 		case tcell.KeyEnter:
-			in.content.Insert('\n')
+			in.edit(editInsert, '\n', func() { in.insert("\n") })
 		case tcell.KeyBackspace, tcell.KeyBackspace2:
-			in.content.KeyBackspace()
+			in.edit(editDelete, 0, func() {
+				if !in.deleteSelected() {
+					in.backspace()
+				}
+			})
 		case tcell.KeyDelete:
-			in.content.KeyDel()
-		default:
-			in.content.Insert(ev.Rune())
+			if shift {
+				in.Cut()
+				break
+			}
+			in.edit(editDelete, 0, func() {
+				if !in.deleteSelected() {
+					in.del()
+				}
+			})
+		case tcell.KeyRune:
+			r := ev.Rune()
+			in.edit(editInsert, r, func() { in.insert(string(r)) })
+		}
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// SpinBox is numeric input with arrows for change of value:
+//
+//	12.50-+
+type SpinBox struct {
+	InputBox
+	Min, Max  float64 // range of value, if Min == Max then range is not checked
+	Step      float64 // step of arrows, keys Up, Down and mouse wheel, if zero then 1
+	Precision uint    // amount of digits after point, if zero then value is integer
+
+	// OnChange is called after change of value
+	OnChange func(value float64)
+
+	value float64
+	init  bool
+}
+
+// spinArrows is width of arrows
+const spinArrows = 2
+
+// SetValue set value without call of OnChange
+func (sb *SpinBox) SetValue(value float64) {
+	if !sb.init {
+		sb.Filter(func(r rune) bool {
+			return unicode.IsDigit(r) || r == '-' || r == '+' ||
+				(r == '.' && 0 < sb.Precision)
+		})
+	}
+	sb.init = true
+	sb.value = sb.round(value)
+	sb.InputBox.SetText(sb.format(sb.value))
+}
+
+// GetValue return value
+func (sb *SpinBox) GetValue() float64 {
+	return sb.value
+}
+
+// format return text of value
+func (sb *SpinBox) format(value float64) string {
+	return strconv.FormatFloat(value, 'f', int(sb.Precision), 64)
+}
+
+// round return value inside range and with precision
+func (sb *SpinBox) round(value float64) float64 {
+	if sb.Min < sb.Max {
+		if value < sb.Min {
+			value = sb.Min
+		}
+		if sb.Max < value {
+			value = sb.Max
+		}
+	}
+	value, _ = strconv.ParseFloat(sb.format(value), 64)
+	return value
+}
+
+// change value and call OnChange if value is changed
+func (sb *SpinBox) change(value float64) {
+	value = sb.round(value)
+	changed := value != sb.value
+	sb.SetValue(value)
+	sb.setCursor(len(sb.content.GetText()))
+	if f := sb.OnChange; changed && f != nil {
+		f(value)
+	}
+}
+
+// validate typed text. Not valid text is replaced by previous value.
+func (sb *SpinBox) validate() {
+	value, err := strconv.ParseFloat(strings.TrimSpace(sb.GetText()), 64)
+	if err != nil {
+		value = sb.value
+	}
+	sb.change(value)
+}
+
+// spin change value by amount of steps
+func (sb *SpinBox) spin(steps float64) {
+	step := sb.Step
+	if step == 0 {
+		step = 1
+	}
+	sb.validate()
+	sb.change(sb.value + steps*step)
+}
+
+// Focus ...
+// snippet focus.doc
+// For changing focus-state of widget
+// end focus.doc
+func (sb *SpinBox) Focus(focus bool) {
+	if !focus && sb.focus {
+		sb.validate()
+	}
+	sb.InputBox.Focus(focus)
+}
+
+// Render ...
+// snippet render.doc
+// Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
+// end render.doc
+func (sb *SpinBox) Render(width uint, dr Drawer) (height uint) {
+	theme := sb.theme()
+	defer func() {
+		sb.StoreSize(width, height)
+	}()
+	if width <= spinArrows {
+		width, height = 0, 0
+		return
+	}
+	if !sb.init {
+		sb.SetValue(sb.value)
+	}
+	height = sb.InputBox.Render(width-spinArrows, DrawerLimit(
+		dr,
+		0, 0,
+		0, maxSize,
+		0, width-spinArrows-1,
+	))
+	st := theme.ButtonStyle
+	if sb.focus {
+		st = theme.ButtonFocusStyle
+	}
+	dr(0, width-2, st, theme.SpinDown)
+	dr(0, width-1, st, theme.SpinUp)
+	return
+}
+
+// Event ...
+// snippet event.doc
+// For create action for widget
+// end event.doc
+func (sb *SpinBox) Event(ev tcell.Event) {
+	switch ev := ev.(type) {
+	case *tcell.EventMouse:
+		_, ok := sb.onFocus(ev)
+		if !ok {
+			break
+		}
+		col, row := ev.Position()
+		switch {
+		case ev.Buttons()&tcell.WheelUp != 0:
+			sb.InputBox.Focus(true)
+			sb.spin(1)
+			return
+		case ev.Buttons()&tcell.WheelDown != 0:
+			sb.InputBox.Focus(true)
+			sb.spin(-1)
+			return
+		case ev.Buttons() == tcell.Button1 && row == 0 &&
+			int(sb.width)-spinArrows <= col && col < int(sb.width):
+			sb.InputBox.Focus(true)
+			if col == int(sb.width)-spinArrows {
+				sb.spin(-1)
+			} else {
+				sb.spin(1)
+			}
+			return
+		}
+	case *tcell.EventKey:
+		if !sb.focus {
+			break
+		}
+		switch ev.Key() {
+		case tcell.KeyUp:
+			sb.spin(1)
+			return
+		case tcell.KeyDown:
+			sb.spin(-1)
+			return
+		case tcell.KeyEnter:
+			sb.validate()
+			return
 		}
 	}
+	sb.InputBox.Event(ev)
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 
 type CollapsingHeader struct {
+	scoped
 	rootable
 	frame          Frame
 	noBorderClosed bool
@@ -2439,6 +6481,16 @@ func (c *CollapsingHeader) Focus(focus bool) {
 	c.frame.Focus(focus)
 }
 
+// IsFocused return focus-state of widget
+func (c *CollapsingHeader) IsFocused() bool {
+	return c.frame.focus
+}
+
+// Children return internal widgets in focus order
+func (c *CollapsingHeader) Children() []Widget {
+	return []Widget{&c.frame}
+}
+
 func (c *CollapsingHeader) BorderIfClosed(show bool) {
 	c.noBorderClosed = !show
 }
@@ -2474,7 +6526,7 @@ func (c *CollapsingHeader) Render(width uint, dr Drawer) (height uint) {
 		c.frame.NoBorder = c.noBorderClosed
 		c.frame.root = nil
 	}
-	return c.frame.Render(width, dr)
+	return c.pass(&c.frame).Render(width, dr)
 }
 
 // StoreSize ...
@@ -2489,7 +6541,7 @@ func (c *CollapsingHeader) StoreSize(width, height uint) {
 // snippet getsize.doc
 // return for widget sizes
 // end getsize.doc
-func (c CollapsingHeader) GetSize() (width, height uint) {
+func (c *CollapsingHeader) GetSize() (width, height uint) {
 	return c.frame.GetSize()
 }
 
@@ -2538,6 +6590,16 @@ func (l *ListH) Compress() {
 	l.compress = true
 }
 
+// Children return internal widgets in focus order
+func (l *ListH) Children() (ws []Widget) {
+	for i := range l.nodes {
+		if w := l.nodes[i].w; w != nil {
+			ws = append(ws, w)
+		}
+	}
+	return
+}
+
 // Render ...
 // snippet render.doc
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
@@ -2553,7 +6615,7 @@ func (l *ListH) Render(width uint, dr Drawer) (height uint) {
 		if l.compress {
 			for i := range l.nodes {
 				// initialize sizes of widgets
-				l.nodes[i].w.Render(width, NilDrawer)
+				l.pass(l.nodes[i].w).Render(width, NilDrawer)
 			}
 			l.nodes[0].from = 0
 			for i := range l.nodes {
@@ -2633,7 +6695,7 @@ func (l *ListH) Render(width uint, dr Drawer) (height uint) {
 		if l.nodes[i].w == nil {
 			continue
 		}
-		h := l.nodes[i].w.Render(uint(l.nodes[i].to-l.nodes[i].from), draw)
+		h := l.pass(l.nodes[i].w).Render(uint(l.nodes[i].to-l.nodes[i].from), draw)
 		if height < h {
 			height = h
 		}
@@ -2645,98 +6707,471 @@ func (l *ListH) Render(width uint, dr Drawer) (height uint) {
 // snippet setheight.doc
 // Store maximal height of widget.
 // end setheight.doc
-func (l *ListH) SetHeight(hmax uint) {
-	l.ContainerVerticalFix.SetHeight(hmax)
-	if len(l.nodes) == 0 {
+func (l *ListH) SetHeight(hmax uint) {
+	l.ContainerVerticalFix.SetHeight(hmax)
+	if len(l.nodes) == 0 {
+		return
+	}
+	l.nodes[len(l.nodes)-1].to = -1
+	for i := range l.nodes {
+		if l.nodes[i].w == nil {
+			continue
+		}
+		if vf, ok := l.nodes[i].w.(VerticalFix); ok {
+			vf.SetHeight(hmax)
+		}
+	}
+}
+
+// Event ...
+// snippet event.doc
+// For create action for widget
+// end event.doc
+func (l *ListH) Event(ev tcell.Event) {
+	_, ok := l.onFocus(ev)
+	if ok {
+		l.Focus(true)
+	}
+	if !l.focus {
+		return
+	}
+	switch ev := ev.(type) {
+	case *tcell.EventMouse:
+		// unfocus
+		l.Focus(false)
+		for i := range l.nodes {
+			if w := l.nodes[i].w; w != nil {
+				w.Focus(false)
+			}
+		}
+		col, row := ev.Position()
+		if col < 0 {
+			return
+		}
+		if int(l.width) < col {
+			return
+		}
+		if row < 0 {
+			return
+		}
+		// find focus widget
+		for i := range l.nodes {
+			if l.nodes[i].w == nil {
+				continue
+			}
+			if l.nodes[i].from <= col && col < l.nodes[i].to {
+				// row correction
+				col := col - int(l.nodes[i].from)
+				// focus
+				l.Focus(true)
+				//l.ws[i].Focus(true)
+				l.nodes[i].w.Event(tcell.NewEventMouse(
+					col, row,
+					ev.Buttons(),
+					ev.Modifiers()))
+				return
+			}
+		}
+	case *tcell.EventKey:
+		// send only to focused widgets
+		for i := range l.nodes {
+			if w := l.nodes[i].w; w != nil && isFocused(w) {
+				w.Event(ev)
+			}
+		}
+	}
+}
+
+func (l *ListH) Add(w Widget) {
+	l.nodes = append(l.nodes, listNode{w: w, from: 0, to: 0})
+}
+
+// Size ...
+// snippet size.doc
+// return size of widget list
+// end size.doc
+func (l *ListH) Size() int {
+	return len(l.nodes)
+}
+
+// Clear ...
+// snippet clear.doc
+// reset internal list
+// end clear.doc
+func (l *ListH) Clear() {
+	l.nodes = nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// SplitPane is two widgets with divider between them.
+// Widgets are placed one above another or side by side, if Horizontal
+// is true. Divider is moved by mouse drag, or by arrow keys and keys
+// Home, End when divider is focused.
+// Position of divider may be stored by GetPosition and restored by
+// SetPosition, for example:
+//
+//	var sp SplitPane
+//	sp.Horizontal = true
+//	sp.SetFirst(&tree)
+//	sp.SetSecond(&viewer)
+//	sp.FirstMin = 10
+//	sp.SetPosition(config.Position)
+//	sp.OnChange = func(pos uint) { config.Position = pos }
+type SplitPane struct {
+	ContainerVerticalFix
+	Horizontal bool
+
+	// minimal and maximal size of panes, zero is without limit
+	FirstMin, FirstMax   uint
+	SecondMin, SecondMax uint
+
+	// OnChange is called after moving of divider by user
+	OnChange func(position uint)
+
+	first, second Widget
+	divider       splitDivider
+
+	position   uint // position of divider
+	positioned bool // position is set by user
+	actual     uint // position of divider at last rendering
+	size       uint // size of panes with divider at last rendering
+}
+
+// splitDivider is focusable divider of SplitPane
+type splitDivider struct {
+	container
+	pane *SplitPane
+}
+
+// SetFirst set top or left widget
+func (sp *SplitPane) SetFirst(w Widget) {
+	sp.first = w
+}
+
+// SetSecond set bottom or right widget
+func (sp *SplitPane) SetSecond(w Widget) {
+	sp.second = w
+}
+
+// GetPosition return position of divider. Position is size of first
+// pane in rows or columns.
+func (sp *SplitPane) GetPosition() uint {
+	if !sp.positioned {
+		return sp.actual
+	}
+	return sp.position
+}
+
+// SetPosition set position of divider. Position is limited by sizes of
+// panes at rendering.
+func (sp *SplitPane) SetPosition(position uint) {
+	sp.position = position
+	sp.positioned = true
+}
+
+// limit return position of divider inside panes with size `size`
+func (sp *SplitPane) limit(position, size uint) uint {
+	if size == 0 {
+		return 0
+	}
+	free := size - 1 // without divider
+	if position < sp.FirstMin {
+		position = sp.FirstMin
+	}
+	if 0 < sp.FirstMax && sp.FirstMax < position {
+		position = sp.FirstMax
+	}
+	if 0 < sp.SecondMax && position+sp.SecondMax < free {
+		position = free - sp.SecondMax
+	}
+	if free < position+sp.SecondMin {
+		if sp.SecondMin < free {
+			position = free - sp.SecondMin
+		} else {
+			position = 0
+		}
+	}
+	if free < position {
+		position = free
+	}
+	return position
+}
+
+// move divider to position by user
+func (sp *SplitPane) move(position int) {
+	if position < 0 {
+		position = 0
+	}
+	sp.SetPosition(sp.limit(uint(position), sp.size))
+	sp.actual = sp.position
+	if f := sp.OnChange; f != nil {
+		f(sp.position)
+	}
+}
+
+// Focus ...
+// snippet focus.doc
+// For changing focus-state of widget
+// end focus.doc
+func (sp *SplitPane) Focus(focus bool) {
+	if !focus {
+		for _, w := range sp.Children() {
+			w.Focus(focus)
+		}
+	}
+	sp.focus = focus
+}
+
+// Children return internal widgets in focus order
+func (sp *SplitPane) Children() (ws []Widget) {
+	sp.divider.pane = sp
+	if sp.first != nil {
+		ws = append(ws, sp.first)
+	}
+	ws = append(ws, &sp.divider)
+	if sp.second != nil {
+		ws = append(ws, sp.second)
+	}
+	return
+}
+
+// SetHeight ...
+// snippet setheight.doc
+// Store maximal height of widget.
+// end setheight.doc
+func (sp *SplitPane) SetHeight(hmax uint) {
+	sp.ContainerVerticalFix.SetHeight(hmax)
+	if !sp.Horizontal {
+		// heights of panes are calculated at rendering
 		return
 	}
-	l.nodes[len(l.nodes)-1].to = -1
-	for i := range l.nodes {
-		if l.nodes[i].w == nil {
-			continue
-		}
-		if vf, ok := l.nodes[i].w.(VerticalFix); ok {
+	for _, w := range []Widget{sp.first, sp.second} {
+		if vf, ok := w.(VerticalFix); ok {
 			vf.SetHeight(hmax)
 		}
 	}
 }
 
+// Render ...
+// snippet render.doc
+// Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
+// end render.doc
+func (sp *SplitPane) Render(width uint, dr Drawer) (height uint) {
+	theme := sp.theme()
+	defer func() {
+		sp.StoreSize(width, height)
+	}()
+	sp.divider.pane = sp
+	if width == 0 {
+		return
+	}
+	// render widget inside rectangle
+	render := func(w Widget, row, col, width, height uint) (h uint) {
+		if w == nil || width == 0 {
+			return
+		}
+		if sp.addlimit && height == 0 {
+			return
+		}
+		if !sp.addlimit && !sp.Horizontal {
+			height = maxSize - row
+		}
+		return sp.pass(w).Render(width, DrawerLimit(
+			dr,
+			row, col,
+			row, row+height-1,
+			col, col+width-1,
+		))
+	}
+	if sp.Horizontal {
+		sp.size = width
+		pos := (width - 1) / 2
+		if sp.positioned {
+			pos = sp.position
+		}
+		pos = sp.limit(pos, sp.size)
+		sp.actual = pos
+		h1 := render(sp.first, 0, 0, pos, sp.hmax)
+		h2 := render(sp.second, 0, pos+1, width-pos-1, sp.hmax)
+		height = h1
+		if height < h2 {
+			height = h2
+		}
+		if sp.addlimit {
+			height = sp.hmax
+		}
+		sp.divider.StoreSize(1, height)
+		for row := uint(0); row < height; row++ {
+			dr(row, pos, theme.TextStyle, sp.divider.symbol())
+		}
+		return
+	}
+	// vertical panes
+	if sp.addlimit {
+		sp.size = sp.hmax
+	} else {
+		// size by heights of widgets
+		sp.size = 1
+		for _, w := range []Widget{sp.first, sp.second} {
+			if w != nil {
+				sp.size += sp.pass(w).Render(width, NilDrawer)
+			}
+		}
+	}
+	if sp.size == 0 {
+		return
+	}
+	pos := (sp.size - 1) / 2
+	switch {
+	case sp.positioned:
+		pos = sp.position
+	case !sp.addlimit && sp.first != nil:
+		pos = sp.pass(sp.first).Render(width, NilDrawer)
+	}
+	pos = sp.limit(pos, sp.size)
+	sp.actual = pos
+	if sp.addlimit {
+		if vf, ok := sp.first.(VerticalFix); ok {
+			vf.SetHeight(pos)
+		}
+		if vf, ok := sp.second.(VerticalFix); ok {
+			vf.SetHeight(sp.size - pos - 1)
+		}
+	}
+	render(sp.first, 0, 0, width, pos)
+	sp.divider.StoreSize(width, 1)
+	for col := uint(0); col < width; col++ {
+		dr(pos, col, theme.TextStyle, sp.divider.symbol())
+	}
+	h2 := render(sp.second, pos+1, 0, width, sp.size-pos-1)
+	height = sp.size
+	if !sp.addlimit {
+		height = pos + 1 + h2
+	}
+	return
+}
+
 // Event ...
 // snippet event.doc
 // For create action for widget
 // end event.doc
-func (l *ListH) Event(ev tcell.Event) {
-	_, ok := l.onFocus(ev)
+func (sp *SplitPane) Event(ev tcell.Event) {
+	sp.divider.pane = sp
+	if ev, ok := ev.(*EventDrag); ok {
+		if ev.State == DragMove {
+			col, row := ev.Position()
+			if sp.Horizontal {
+				sp.move(col)
+			} else {
+				sp.move(row)
+			}
+		}
+		return
+	}
+	button, ok := sp.onFocus(ev)
 	if ok {
-		l.Focus(true)
+		sp.Focus(true)
 	}
-	if !l.focus {
+	if !sp.focus {
 		return
 	}
 	switch ev := ev.(type) {
 	case *tcell.EventMouse:
 		// unfocus
-		l.Focus(false)
-		for i := range l.nodes {
-			if w := l.nodes[i].w; w != nil {
-				w.Focus(false)
-			}
-		}
+		sp.Focus(false)
 		col, row := ev.Position()
-		if col < 0 {
-			return
-		}
-		if int(l.width) < col {
+		if col < 0 || row < 0 {
 			return
 		}
-		if row < 0 {
-			return
+		sp.Focus(true)
+		pos, on := row, sp.actual
+		if sp.Horizontal {
+			pos = col
 		}
-		// find focus widget
-		for i := range l.nodes {
-			if l.nodes[i].w == nil {
-				continue
+		switch {
+		case pos == int(on):
+			sp.divider.Focus(true)
+			if button[0] {
+				CaptureMouse(sp, ev)
 			}
-			if l.nodes[i].from <= col && col < l.nodes[i].to {
-				// row correction
-				col := col - int(l.nodes[i].from)
-				// focus
-				l.Focus(true)
-				//l.ws[i].Focus(true)
-				l.nodes[i].w.Event(tcell.NewEventMouse(
+		case pos < int(on):
+			if sp.first != nil {
+				sp.first.Event(ev)
+			}
+		default:
+			if sp.Horizontal {
+				col -= int(on) + 1
+			} else {
+				row -= int(on) + 1
+			}
+			if sp.second != nil {
+				sp.second.Event(tcell.NewEventMouse(
 					col, row,
 					ev.Buttons(),
 					ev.Modifiers()))
-				return
 			}
 		}
 	case *tcell.EventKey:
-		for i := range l.nodes {
-			if w := l.nodes[i].w; w != nil {
+		// send only to focused widgets
+		for _, w := range sp.Children() {
+			if isFocused(w) {
 				w.Event(ev)
 			}
 		}
 	}
 }
 
-func (l *ListH) Add(w Widget) {
-	l.nodes = append(l.nodes, listNode{w: w, from: 0, to: 0})
+// AcceptFocus return true if widget may be focused by keyboard
+func (d *splitDivider) AcceptFocus() bool { return true }
+
+// symbol return rune of divider
+func (d *splitDivider) symbol() rune {
+	theme := d.theme()
+	switch {
+	case d.pane.Horizontal && d.focus:
+		return theme.LineVerticalFocus
+	case d.pane.Horizontal:
+		return theme.LineVerticalUnfocus
+	case d.focus:
+		return theme.LineHorizontalFocus
+	}
+	return theme.LineHorizontalUnfocus
 }
 
-// Size ...
-// snippet size.doc
-// return size of widget list
-// end size.doc
-func (l *ListH) Size() int {
-	return len(l.nodes)
+// Render ...
+// snippet render.doc
+// Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
+// end render.doc
+func (d *splitDivider) Render(width uint, dr Drawer) (height uint) {
+	// divider is drawn by split pane
+	return
 }
 
-// Clear ...
-// snippet clear.doc
-// reset internal list
-// end clear.doc
-func (l *ListH) Clear() {
-	l.nodes = nil
+// Event ...
+// snippet event.doc
+// For create action for widget
+// end event.doc
+func (d *splitDivider) Event(ev tcell.Event) {
+	key, ok := ev.(*tcell.EventKey)
+	if !ok || d.pane == nil {
+		return
+	}
+	sp := d.pane
+	back, forward := tcell.KeyUp, tcell.KeyDown
+	if sp.Horizontal {
+		back, forward = tcell.KeyLeft, tcell.KeyRight
+	}
+	switch key.Key() {
+	case back:
+		sp.move(int(sp.actual) - 1)
+	case forward:
+		sp.move(int(sp.actual) + 1)
+	case tcell.KeyHome:
+		sp.move(0)
+	case tcell.KeyEnd:
+		sp.move(int(sp.size))
+	}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -2753,6 +7188,7 @@ func (l *ListH) Clear() {
 //	|                   |
 //	+-------------------+
 type ComboBox struct {
+	scoped
 	ch       CollapsingHeader
 	rg       RadioGroup
 	ts       []string
@@ -2776,113 +7212,479 @@ func (c *ComboBox) Add(ts ...string) {
 // snippet clear.doc
 // reset internal list
 // end clear.doc
-func (c *ComboBox) Clear() {
-	c.rg.Clear()
-	c.ts = []string{}
-	c.OnChange = nil
+func (c *ComboBox) Clear() {
+	c.rg.Clear()
+	c.ts = []string{}
+	c.OnChange = nil
+}
+
+func (c *ComboBox) SetPos(pos uint) {
+	c.rg.SetPos(pos)
+	if f := c.OnChange; f != nil {
+		f()
+	}
+	if f := c.rg.OnChange; f != nil {
+		f()
+	}
+}
+
+func (c *ComboBox) GetPos() uint {
+	if c == nil { // avoid nil ComboBox
+		return 0
+	}
+	return c.rg.GetPos()
+}
+
+func (c *ComboBox) checkUpdater() {
+	if c.rg.OnChange != nil {
+		return
+	}
+	c.rg.OnChange = func() {
+		c.ch.SetText("")
+		if len(c.ts) == 0 {
+			// empty list
+			return
+		}
+		// c.rg.pos = -1; int(c.rg.pos) = 18446744073709551615
+		// So, use `uint(len(c.ts)) <= c.rg.pos`
+		if uint(len(c.ts)) <= c.rg.pos {
+			// outside of range - this is strange
+			// try to analyze your code
+			if 0 < len(c.ts) {
+				c.rg.pos = uint(len(c.ts) - 1)
+			} else {
+				return
+			}
+		}
+		c.ch.SetText(c.ts[c.rg.pos])
+		if f := c.OnChange; f != nil {
+			f()
+		}
+	}
+	c.rg.OnChange()
+}
+
+// Render ...
+// snippet render.doc
+// Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
+// end render.doc
+func (c *ComboBox) Render(width uint, dr Drawer) (height uint) {
+	defer func() {
+		c.StoreSize(width, height)
+	}()
+	if width < 4 {
+		return 1
+	}
+	if c.ch.root == nil {
+		c.ch.root = &c.rg
+	}
+	c.checkUpdater()
+	return c.pass(&c.ch).Render(width, dr)
+}
+
+// StoreSize ...
+// snippet storesize.doc
+// For storing widget sizes.
+// end storesize.doc
+func (c *ComboBox) StoreSize(width, height uint) {
+	c.ch.StoreSize(width, height)
+}
+
+// GetSize ...
+// snippet getsize.doc
+// return for widget sizes
+// end getsize.doc
+func (c *ComboBox) GetSize() (width, height uint) {
+	return c.ch.GetSize()
+}
+
+// Focus ...
+// snippet focus.doc
+// For changing focus-state of widget
+// end focus.doc
+func (c *ComboBox) Focus(focus bool) { c.ch.Focus(focus) }
+
+// IsFocused return focus-state of widget
+func (c *ComboBox) IsFocused() bool { return c.ch.IsFocused() }
+
+// Children return internal widgets in focus order
+func (c *ComboBox) Children() []Widget { return []Widget{&c.ch} }
+
+// Event ...
+// snippet event.doc
+// For create action for widget
+// end event.doc
+func (c *ComboBox) Event(ev tcell.Event) {
+	c.ch.Event(ev)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var _ Widget = (*Table)(nil)
+
+// ColumnPolicy is policy of table column width
+type ColumnPolicy uint8
+
+const (
+	ColumnFit    ColumnPolicy = iota // width by content
+	ColumnFixed                      // fixed width
+	ColumnWeight                     // part of free width in according to weight
+)
+
+// TableColumn is property of table column
+type TableColumn struct {
+	Name   string
+	Policy ColumnPolicy
+	Value  uint // width for ColumnFixed, weight for ColumnWeight
+}
+
+// Table examples
+//
+//	Name    |Size|Comment
+//	--------+----+---------------
+//	main.go | 102|Main file
+//	vl.go   |8000|Widgets
+//	readme.m| 172|Very long comm~
+type Table struct {
+	ContainerVerticalFix
+
+	columns  []TableColumn
+	rows     [][]string
+	widths   []uint
+	selected int  // selected row, -1 if not selected
+	offset   uint // first visible row
+	init     bool
+
+	OnChange func()
+}
+
+// tableHeader is amount of rows for table header
+const tableHeader uint = 2
+
+// SetColumns set columns of table
+func (t *Table) SetColumns(columns ...TableColumn) {
+	t.columns = columns
+}
+
+// AddRow add row of table
+func (t *Table) AddRow(cells ...string) {
+	row := make([]string, len(cells))
+	for i := range cells {
+		row[i] = strings.ReplaceAll(cells[i], "\n", " ")
+	}
+	t.rows = append(t.rows, row)
+}
+
+// GetRow return cells of table row
+func (t *Table) GetRow(index int) []string {
+	if index < 0 || len(t.rows) <= index {
+		// not valid index
+		return nil
+	}
+	return append([]string(nil), t.rows[index]...)
+}
+
+// Size ...
+// snippet size.doc
+// return size of widget list
+// end size.doc
+func (t *Table) Size() int {
+	return len(t.rows)
+}
+
+// Clear ...
+// snippet clear.doc
+// reset internal list
+// end clear.doc
+func (t *Table) Clear() {
+	t.rows = nil
+	t.selected = -1
+	t.offset = 0
 }
 
-func (c *ComboBox) SetPos(pos uint) {
-	c.rg.SetPos(pos)
-	if f := c.OnChange; f != nil {
-		f()
+// SetSelected set selected row
+func (t *Table) SetSelected(index int) {
+	t.prepare()
+	if len(t.rows) <= index {
+		index = len(t.rows) - 1
 	}
-	if f := c.rg.OnChange; f != nil {
+	if index < 0 {
+		index = -1
+	}
+	if index == t.selected {
+		return
+	}
+	t.selected = index
+	t.fixOffset()
+	if f := t.OnChange; f != nil {
 		f()
 	}
 }
 
-func (c *ComboBox) GetPos() uint {
-	if c == nil { // avoid nil ComboBox
+// GetSelected return selected row or -1 if row is not selected
+func (t *Table) GetSelected() int {
+	t.prepare()
+	return t.selected
+}
+
+func (t *Table) prepare() {
+	if t.init {
+		return
+	}
+	t.selected = -1
+	t.init = true
+}
+
+// AcceptFocus return true if widget may be focused by keyboard
+func (t *Table) AcceptFocus() bool { return true }
+
+// visible return amount of visible table rows
+func (t *Table) visible() uint {
+	if !t.addlimit {
+		return uint(len(t.rows))
+	}
+	if t.hmax < tableHeader {
 		return 0
 	}
-	return c.rg.GetPos()
+	return t.hmax - tableHeader
 }
 
-func (c *ComboBox) checkUpdater() {
-	if c.rg.OnChange != nil {
+// fixOffset show selected row
+func (t *Table) fixOffset() {
+	if !t.addlimit {
+		t.offset = 0
 		return
 	}
-	c.rg.OnChange = func() {
-		c.ch.SetText("")
-		if len(c.ts) == 0 {
-			// empty list
-			return
+	size := t.visible()
+	if 0 <= t.selected {
+		sel := uint(t.selected)
+		if sel < t.offset {
+			t.offset = sel
 		}
-		// c.rg.pos = -1; int(c.rg.pos) = 18446744073709551615
-		// So, use `uint(len(c.ts)) <= c.rg.pos`
-		if uint(len(c.ts)) <= c.rg.pos {
-			// outside of range - this is strange
-			// try to analyze your code
-			if 0 < len(c.ts) {
-				c.rg.pos = uint(len(c.ts) - 1)
+		if 0 < size && t.offset+size <= sel {
+			t.offset = sel - size + 1
+		}
+	}
+	t.clampOffset()
+}
+
+// clampOffset avoid empty rows at the end of table
+func (t *Table) clampOffset() {
+	if !t.addlimit {
+		t.offset = 0
+		return
+	}
+	size := t.visible()
+	if uint(len(t.rows)) < t.offset+size {
+		if size < uint(len(t.rows)) {
+			t.offset = uint(len(t.rows)) - size
+		} else {
+			t.offset = 0
+		}
+	}
+}
+
+// columnWidths return widths of columns
+func (t *Table) columnWidths(width uint) []uint {
+	ws := make([]uint, len(t.columns))
+	var used, weights uint
+	for i, c := range t.columns {
+		switch c.Policy {
+		case ColumnFixed:
+			ws[i] = c.Value
+		case ColumnWeight:
+			if c.Value == 0 {
+				weights++
 			} else {
-				return
+				weights += c.Value
+			}
+		default: // ColumnFit
+			ws[i] = uint(len([]rune(c.Name)))
+			for _, row := range t.rows {
+				if i < len(row) {
+					if w := uint(len([]rune(row[i]))); ws[i] < w {
+						ws[i] = w
+					}
+				}
 			}
 		}
-		c.ch.SetText(c.ts[c.rg.pos])
-		if f := c.OnChange; f != nil {
-			f()
+		used += ws[i]
+	}
+	used += uint(len(t.columns) - 1) // borders
+	if weights == 0 || width <= used {
+		return ws
+	}
+	free := width - used
+	var last int
+	for i, c := range t.columns {
+		if c.Policy != ColumnWeight {
+			continue
+		}
+		weight := c.Value
+		if weight == 0 {
+			weight = 1
+		}
+		ws[i] = free * weight / weights
+		used += ws[i]
+		last = i
+	}
+	// add rest of width to last weight column
+	ws[last] += width - used
+	return ws
+}
+
+// renderRow draw single row of table
+func (t *Table) renderRow(row, width uint, cells []string, st tcell.Style, dr Drawer) {
+	theme := t.theme()
+	for col := uint(0); col < width; col++ {
+		dr(row, col, st, ' ')
+	}
+	var pos uint
+	for c := range t.columns {
+		if width <= pos {
+			return
+		}
+		w := t.widths[c]
+		var rs []rune
+		if c < len(cells) {
+			rs = []rune(cells[c])
+		}
+		if w < uint(len(rs)) {
+			rs = rs[:w]
+			if 0 < w {
+				rs[w-1] = theme.TableTruncate
+			}
+		}
+		for i := range rs {
+			if width <= pos+uint(i) {
+				break
+			}
+			dr(row, pos+uint(i), st, rs[i])
+		}
+		pos += w
+		if c != len(t.columns)-1 && pos < width {
+			dr(row, pos, st, theme.LineVerticalUnfocus)
+			pos++
 		}
 	}
-	c.rg.OnChange()
 }
 
 // Render ...
 // snippet render.doc
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
 // end render.doc
-func (c *ComboBox) Render(width uint, dr Drawer) (height uint) {
+func (t *Table) Render(width uint, dr Drawer) (height uint) {
+	theme := t.theme()
 	defer func() {
-		c.StoreSize(width, height)
+		t.StoreSize(width, height)
 	}()
-	if width < 4 {
-		return 1
+	t.prepare()
+	if width < 2 || len(t.columns) == 0 {
+		return
 	}
-	if c.ch.root == nil {
-		c.ch.root = &c.rg
+	t.widths = t.columnWidths(width)
+	t.clampOffset()
+	// header
+	names := make([]string, len(t.columns))
+	for i := range t.columns {
+		names[i] = t.columns[i].Name
+	}
+	t.renderRow(0, width, names, theme.TableHeaderStyle, dr)
+	// line under header
+	var pos uint
+	for c := range t.columns {
+		pos += t.widths[c]
+		for col := pos - t.widths[c]; col < pos && col < width; col++ {
+			dr(1, col, theme.TextStyle, theme.LineHorizontalUnfocus)
+		}
+		if c != len(t.columns)-1 && pos < width {
+			dr(1, pos, theme.TextStyle, theme.TableCross)
+			pos++
+		}
+	}
+	height = tableHeader
+	// rows
+	size := t.visible()
+	for i := t.offset; i < uint(len(t.rows)) && i < t.offset+size; i++ {
+		st := theme.TextStyle
+		if int(i) == t.selected {
+			st = theme.ButtonSelectStyle
+			if t.focus {
+				st = theme.ButtonFocusStyle
+			}
+		}
+		t.renderRow(height, width, t.rows[i], st, dr)
+		height++
 	}
-	c.checkUpdater()
-	return c.ch.Render(width, dr)
-}
-
-// StoreSize ...
-// snippet storesize.doc
-// For storing widget sizes.
-// end storesize.doc
-func (c *ComboBox) StoreSize(width, height uint) {
-	c.ch.StoreSize(width, height)
-}
-
-// GetSize ...
-// snippet getsize.doc
-// return for widget sizes
-// end getsize.doc
-func (c ComboBox) GetSize() (width, height uint) {
-	return c.ch.GetSize()
+	if t.addlimit {
+		height = t.hmax
+	}
+	return
 }
 
-// Focus ...
-// snippet focus.doc
-// For changing focus-state of widget
-// end focus.doc
-func (c *ComboBox) Focus(focus bool) { c.ch.Focus(focus) }
-
 // Event ...
 // snippet event.doc
 // For create action for widget
 // end event.doc
-func (c *ComboBox) Event(ev tcell.Event) {
-	c.ch.Event(ev)
+func (t *Table) Event(ev tcell.Event) {
+	mouse, ok := t.onFocus(ev)
+	if ok {
+		t.Focus(true)
+	}
+	if !t.focus {
+		return
+	}
+	t.prepare()
+	switch ev := ev.(type) {
+	case *tcell.EventMouse:
+		switch ev.Buttons() {
+		case tcell.WheelUp:
+			if 0 < t.offset {
+				t.offset--
+			}
+		case tcell.WheelDown:
+			if t.offset+t.visible() < uint(len(t.rows)) {
+				t.offset++
+			}
+		}
+		if !mouse[0] {
+			break
+		}
+		if _, row := ev.Position(); int(tableHeader) <= row {
+			if pos := int(t.offset) + row - int(tableHeader); pos < len(t.rows) {
+				t.SetSelected(pos)
+			}
+		}
+	case *tcell.EventKey:
+		page := int(t.visible())
+		if page < 1 {
+			page = 1
+		}
+		switch ev.Key() {
+		case tcell.KeyUp:
+			if 0 < t.selected {
+				t.SetSelected(t.selected - 1)
+			}
+		case tcell.KeyDown:
+			t.SetSelected(t.selected + 1)
+		case tcell.KeyHome:
+			t.SetSelected(0)
+		case tcell.KeyEnd:
+			t.SetSelected(len(t.rows) - 1)
+		case tcell.KeyPgUp:
+			if t.selected < page {
+				t.SetSelected(0)
+			} else {
+				t.SetSelected(t.selected - page)
+			}
+		case tcell.KeyPgDn:
+			t.SetSelected(t.selected + page)
+		}
+	}
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 
-// TODO
-// Widget: Table
-
-///////////////////////////////////////////////////////////////////////////////
-
 // TODO
 // Tabs examples:
 //
@@ -2986,99 +7788,588 @@ func (t *Tabs) UseCombo(combo bool) {
 		t.Frame.Header = t.headerCombo
 		return
 	}
-	// from ComboBox to ListH
-	t.Frame.Header = t.headerListH
-	t.headerListH.Compress()
+	// from ComboBox to ListH
+	t.Frame.Header = t.headerListH
+	t.headerListH.Compress()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+type Stack struct {
+	scoped
+	widgets []WidgetVerticalFix
+}
+
+func (s *Stack) Push(w WidgetVerticalFix) {
+	s.widgets = append(s.widgets, w)
+}
+func (s *Stack) Pop() {
+	if len(s.widgets) == 0 {
+		return
+	}
+	s.widgets = s.widgets[:len(s.widgets)-1]
+}
+
+func (s *Stack) present() WidgetVerticalFix {
+	if len(s.widgets) == 0 {
+		sc := new(Scroll)
+		sc.SetRoot(TextStatic("stack is empty"))
+		return sc
+	}
+	return s.widgets[len(s.widgets)-1]
+}
+
+// Focus ...
+// snippet focus.doc
+// For changing focus-state of widget
+// end focus.doc
+func (s *Stack) Focus(focus bool) {
+	s.present().Focus(focus)
+}
+
+// IsFocused return focus-state of widget
+func (s *Stack) IsFocused() bool {
+	return isFocused(s.present())
+}
+
+// Children return internal widgets in focus order
+func (s *Stack) Children() []Widget {
+	return []Widget{s.present()}
+}
+
+// Render ...
+// snippet render.doc
+// Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
+// end render.doc
+func (s *Stack) Render(width uint, dr Drawer) (height uint) {
+	return s.pass(s.present()).Render(width, dr)
+}
+
+// Event ...
+// snippet event.doc
+// For create action for widget
+// end event.doc
+func (s *Stack) Event(ev tcell.Event) {
+	s.present().Event(ev)
+}
+
+// StoreSize ...
+// snippet storesize.doc
+// For storing widget sizes.
+// end storesize.doc
+func (s *Stack) StoreSize(width, height uint) {
+	s.present().StoreSize(width, height)
+}
+
+// GetSize ...
+// snippet getsize.doc
+// return for widget sizes
+// end getsize.doc
+func (s *Stack) GetSize() (width, height uint) {
+	return s.present().GetSize()
+}
+
+// SetHeight ...
+// snippet setheight.doc
+// Store maximal height of widget.
+// end setheight.doc
+func (s *Stack) SetHeight(hmax uint) {
+	s.present().SetHeight(hmax)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+var _ Widget = (*FileDialog)(nil)
+
+// FileMode is mode of file dialog
+type FileMode uint8
+
+const (
+	FileOpen   FileMode = iota // choose exist file
+	FileSave                   // choose file for saving
+	FileSaveAs                 // choose not exist file for saving
+)
+
+// fileListHeight is default height of file list in file dialog
+const fileListHeight uint = 10
+
+// FileDialog examples
+//
+//	Directory: docs
+//	[ ../ ]                -
+//	[ images/ ]            |
+//	[ readme.md ]          *
+//	[ todo.md ]            -
+//	Filter: *.md
+//	File name:
+//	readme.md
+//	[ Open ] [ Cancel ]
+//
+// Path of files is relative to root of file system `fs.FS`.
+// By default file system is `os.DirFS(".")`.
+type FileDialog struct {
+	scoped
+	list    List
+	dir     Text
+	view    List // limit height of file list
+	scroll  Scroll
+	files   List
+	filter  Text
+	name    InputBox
+	info    Text
+	buttons ListH
+	action  Button
+	cancel  Button
+
+	fsys    fs.FS
+	cwd     string   // present directory
+	filters []string // glob patterns of visible files
+	mode    FileMode
+	confirm string // path of file confirmed for overwrite
+	height  uint   // height of file list
+	init    bool
+	update  bool
+
+	// Validate is additional validation of file name
+	Validate func(name string) error
+	// OnSelect is called with path of choosed file
+	OnSelect func(path string)
+	// OnCancel is called after click on cancel button
+	OnCancel func()
+}
+
+// SetFS set file system for file dialog
+func (fd *FileDialog) SetFS(fsys fs.FS) {
+	fd.fsys = fsys
+	fd.cwd = "."
+	fd.confirm = ""
+	fd.update = false
+}
+
+// SetDir set present directory
+func (fd *FileDialog) SetDir(dir string) {
+	fd.cwd = path.Clean(dir)
+	fd.confirm = ""
+	fd.update = false
+}
+
+// GetDir return present directory
+func (fd *FileDialog) GetDir() string {
+	return fd.cwd
+}
+
+// SetFilter set glob patterns of visible files, for example: "*.go".
+// All files are visible for empty patterns.
+func (fd *FileDialog) SetFilter(patterns ...string) {
+	fd.filters = patterns
+	fd.update = false
+}
+
+// SetMode set mode of file dialog
+func (fd *FileDialog) SetMode(mode FileMode) {
+	fd.mode = mode
+	fd.confirm = ""
+}
+
+// SetListHeight set height of file list.
+// If height is zero, then default height is used.
+func (fd *FileDialog) SetListHeight(height uint) {
+	fd.height = height
+	fd.view.SetHeight(fd.listHeight())
+}
+
+// listHeight return height of file list
+func (fd *FileDialog) listHeight() uint {
+	if fd.height == 0 {
+		return fileListHeight
+	}
+	return fd.height
+}
+
+// SetFileName set name of file
+func (fd *FileDialog) SetFileName(name string) {
+	fd.name.SetText(name)
+}
+
+// GetFileName return name of file
+func (fd *FileDialog) GetFileName() string {
+	return fd.name.GetText()
+}
+
+func (fd *FileDialog) prepare() {
+	if !fd.init {
+		fd.scroll.SetRoot(&fd.files)
+		fd.view.Add(&fd.scroll)
+		fd.view.SetHeight(fd.listHeight())
+		fd.name.Filter(func(r rune) bool { return r != '\n' })
+		fd.action.Compress()
+		fd.action.OnClick = fd.accept
+		fd.cancel.SetText("Cancel")
+		fd.cancel.Compress()
+		fd.cancel.OnClick = func() {
+			if f := fd.OnCancel; f != nil {
+				f()
+			}
+		}
+		fd.buttons.Compress()
+		fd.list.Add(&fd.dir)
+		fd.list.Add(&fd.view)
+		fd.list.Add(&fd.filter)
+		fd.list.Add(TextStatic("File name:"))
+		fd.list.Add(&fd.name)
+		fd.list.Add(&fd.info)
+		fd.list.Add(&fd.buttons)
+		fd.init = true
+	}
+	if label := fd.label(); fd.action.GetText() != label {
+		fd.action.SetText(label)
+		// update widths of buttons
+		fd.buttons.Clear()
+		fd.buttons.Add(&fd.action)
+		fd.buttons.Add(&fd.cancel)
+	}
+	if !fd.update {
+		fd.refresh()
+		fd.update = true
+	}
+}
+
+// label return text of action button
+func (fd *FileDialog) label() string {
+	switch fd.mode {
+	case FileOpen:
+		return "Open"
+	case FileSaveAs:
+		return "Save as"
+	}
+	return "Save"
+}
+
+// refresh update list of files in present directory
+func (fd *FileDialog) refresh() {
+	if fd.fsys == nil {
+		fd.fsys = os.DirFS(".")
+	}
+	if fd.cwd == "" {
+		fd.cwd = "."
+	}
+	fd.dir.SetText("Directory: " + fd.cwd)
+	fd.filter.SetText("Filter: " + strings.Join(fd.filters, " "))
+	if len(fd.filters) == 0 {
+		fd.filter.SetText("Filter: *")
+	}
+	fd.info.SetText("")
+	fd.files.Clear()
+	add := func(name string, OnClick func()) {
+		var btn Button
+		btn.SetText(name)
+		btn.Compress()
+		btn.OnClick = OnClick
+		fd.files.Add(&btn)
+	}
+	if fd.cwd != "." {
+		add("../", func() { fd.SetDir(path.Dir(fd.cwd)) })
+	}
+	entries, err := fs.ReadDir(fd.fsys, fd.cwd)
+	if err != nil {
+		fd.info.SetText(err.Error())
+		return
+	}
+	// directories
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := path.Join(fd.cwd, e.Name())
+		add(e.Name()+"/", func() { fd.SetDir(dir) })
+	}
+	// files
+	for _, e := range entries {
+		if e.IsDir() || !fd.match(e.Name()) {
+			continue
+		}
+		name := e.Name()
+		add(name, func() {
+			fd.name.SetText(name)
+			fd.info.SetText("")
+		})
+	}
 }
 
-///////////////////////////////////////////////////////////////////////////////
-
-type Stack struct {
-	widgets []WidgetVerticalFix
+// match return true if file name is acceptable by filters
+func (fd *FileDialog) match(name string) bool {
+	if len(fd.filters) == 0 {
+		return true
+	}
+	for _, pattern := range fd.filters {
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
 }
 
-func (s *Stack) Push(w WidgetVerticalFix) {
-	s.widgets = append(s.widgets, w)
-}
-func (s *Stack) Pop() {
-	if len(s.widgets) == 0 {
-		return
+// check return error for not valid file name
+func (fd *FileDialog) check(name string) error {
+	if name == "" {
+		return fmt.Errorf("file name is empty")
 	}
-	s.widgets = s.widgets[:len(s.widgets)-1]
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("not valid file name: %q", name)
+	}
+	p := path.Join(fd.cwd, name)
+	if !fs.ValidPath(p) {
+		return fmt.Errorf("not valid path: %q", p)
+	}
+	if f := fd.Validate; f != nil {
+		if err := f(name); err != nil {
+			return err
+		}
+	}
+	info, err := fs.Stat(fd.fsys, p)
+	if err == nil && info.IsDir() {
+		return fmt.Errorf("%q is directory", name)
+	}
+	if err != nil && fd.mode == FileOpen {
+		return fmt.Errorf("file %q is not exist", name)
+	}
+	if err == nil && fd.mode == FileSaveAs {
+		return fmt.Errorf("file %q is exist", name)
+	}
+	return nil
 }
 
-func (s *Stack) present() WidgetVerticalFix {
-	if len(s.widgets) == 0 {
-		sc := new(Scroll)
-		sc.SetRoot(TextStatic("stack is empty"))
-		return sc
+// accept check file name and call OnSelect
+func (fd *FileDialog) accept() {
+	name := strings.TrimSpace(fd.name.GetText())
+	if err := fd.check(name); err != nil {
+		fd.confirm = ""
+		fd.info.SetText(err.Error())
+		return
+	}
+	p := path.Join(fd.cwd, name)
+	if fd.mode == FileSave && fd.confirm != p {
+		if _, err := fs.Stat(fd.fsys, p); err == nil {
+			// file is exist
+			fd.confirm = p
+			fd.info.SetText(fmt.Sprintf("File %q is exist. Press %q again for overwrite",
+				name, fd.label()))
+			return
+		}
+	}
+	fd.confirm = ""
+	fd.info.SetText("")
+	if f := fd.OnSelect; f != nil {
+		f(p)
 	}
-	return s.widgets[len(s.widgets)-1]
 }
 
 // Focus ...
 // snippet focus.doc
 // For changing focus-state of widget
 // end focus.doc
-func (s *Stack) Focus(focus bool) {
-	s.present().Focus(focus)
+func (fd *FileDialog) Focus(focus bool) {
+	fd.list.Focus(focus)
+}
+
+// IsFocused return focus-state of widget
+func (fd *FileDialog) IsFocused() bool {
+	return fd.list.focus
+}
+
+// Children return internal widgets in focus order
+func (fd *FileDialog) Children() []Widget {
+	fd.prepare()
+	return fd.list.Children()
 }
 
 // Render ...
 // snippet render.doc
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
 // end render.doc
-func (s *Stack) Render(width uint, dr Drawer) (height uint) {
-	return s.present().Render(width, dr)
+func (fd *FileDialog) Render(width uint, dr Drawer) (height uint) {
+	fd.prepare()
+	return fd.pass(&fd.list).Render(width, dr)
 }
 
 // Event ...
 // snippet event.doc
 // For create action for widget
 // end event.doc
-func (s *Stack) Event(ev tcell.Event) {
-	s.present().Event(ev)
+func (fd *FileDialog) Event(ev tcell.Event) {
+	fd.prepare()
+	if ev, ok := ev.(*tcell.EventKey); ok {
+		if ev.Key() == tcell.KeyEnter && fd.name.focus {
+			fd.accept()
+			return
+		}
+	}
+	fd.list.Event(ev)
 }
 
 // StoreSize ...
 // snippet storesize.doc
 // For storing widget sizes.
 // end storesize.doc
-func (s *Stack) StoreSize(width, height uint) {
-	s.present().StoreSize(width, height)
+func (fd *FileDialog) StoreSize(width, height uint) {
+	fd.list.StoreSize(width, height)
 }
 
 // GetSize ...
 // snippet getsize.doc
 // return for widget sizes
 // end getsize.doc
-func (s *Stack) GetSize() (width, height uint) {
-	return s.present().GetSize()
-}
-
-// SetHeight ...
-// snippet setheight.doc
-// Store maximal height of widget.
-// end setheight.doc
-func (s *Stack) SetHeight(hmax uint) {
-	s.present().SetHeight(hmax)
+func (fd *FileDialog) GetSize() (width, height uint) {
+	return fd.list.GetSize()
 }
 
 ///////////////////////////////////////////////////////////////////////////////
 
 // TODO
-// Widget: Dialog windows
-// Widget: Open/Save/Save as dialog windows
+// Widget: Menu
 
 ///////////////////////////////////////////////////////////////////////////////
 
-// TODO
-// Widget: Menu
+// ContextMenu is wrapper of root widget with popup menu.
+// Popup menu is opened by right mouse button at the mouse position
+// and closed by click outside of popup or by key Escape.
+// Popup menu is shown only inside Screen.
+//
+//	var cm ContextMenu
+//	cm.SetRoot(&widget)
+//	cm.AddButton("Copy", func() { ... })
+type ContextMenu struct {
+	container
+	rootable
 
-///////////////////////////////////////////////////////////////////////////////
+	holder  Menu // invisible main menu for events of popup
+	popup   Menu
+	request bool // right mouse button is pressed inside root
+}
 
-// TODO
-// Widget: ContextMenu
+// AddButton add button in popup menu
+func (cm *ContextMenu) AddButton(name string, OnClick func()) {
+	cm.popup.AddButton(name, OnClick)
+}
+
+// AddText add text in popup menu
+func (cm *ContextMenu) AddText(name string) {
+	cm.popup.AddText(name)
+}
+
+// AddMenu add submenu in popup menu
+func (cm *ContextMenu) AddMenu(name string, sub *Menu) {
+	cm.popup.AddMenu(name, sub)
+}
+
+// IsOpen return true if popup menu is opened
+func (cm *ContextMenu) IsOpen() bool {
+	return cm.popup.opened
+}
+
+// AcceptEscape return true if key Escape is used for closing of menu
+func (cm *ContextMenu) AcceptEscape() bool { return cm.IsOpen() }
+
+// Close popup menu
+func (cm *ContextMenu) Close() {
+	cm.popup.resetSubmenu()
+	cm.popup.Focus(false)
+}
+
+// open popup menu at screen position
+func (cm *ContextMenu) open(row, col uint) {
+	if cm.popup.parent == nil {
+		cm.holder.subs = []*Menu{&cm.popup}
+		cm.popup.parent = &cm.holder
+	}
+	cm.popup.resetSubmenu()
+	cm.popup.opened = true
+	cm.popup.offset = Offset{row: row, col: col}
+}
+
+// renderPopup draw opened popup menu inside screen with sizes
+// `width` and `height`
+func (cm *ContextMenu) renderPopup(width, height uint, dr Drawer) {
+	// calculate sizes of opened menus
+	_ = cm.pass(&cm.holder).Render(width, NilDrawer)
+	var fit func(menu *Menu)
+	fit = func(menu *Menu) {
+		for _, m := range menu.subs {
+			if m == nil || !m.opened {
+				continue
+			}
+			if _, h := m.frame.GetSize(); height < m.offset.row+h {
+				if h < height {
+					m.offset.row = height - h
+				} else {
+					m.offset.row = 0
+				}
+			}
+			fit(m)
+		}
+	}
+	fit(&cm.holder)
+	_ = cm.pass(&cm.holder).Render(width, dr)
+}
+
+// Focus ...
+// snippet focus.doc
+// For changing focus-state of widget
+// end focus.doc
+func (cm *ContextMenu) Focus(focus bool) {
+	cm.container.Focus(focus)
+	if cm.root != nil {
+		cm.root.Focus(focus)
+	}
+}
+
+// IsFocused return focus-state of widget
+func (cm *ContextMenu) IsFocused() bool {
+	return isFocused(cm.root)
+}
+
+// Children return internal widgets in focus order
+func (cm *ContextMenu) Children() []Widget {
+	return []Widget{cm.root}
+}
+
+// SetHeight ...
+// snippet setheight.doc
+// Store maximal height of widget.
+// end setheight.doc
+func (cm *ContextMenu) SetHeight(hmax uint) {
+	if vf, ok := cm.root.(VerticalFix); ok {
+		vf.SetHeight(hmax)
+	}
+}
+
+// Render ...
+// snippet render.doc
+// Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
+// end render.doc
+func (cm *ContextMenu) Render(width uint, dr Drawer) (height uint) {
+	defer func() {
+		cm.StoreSize(width, height)
+	}()
+	if cm.root == nil {
+		return
+	}
+	return cm.pass(cm.root).Render(width, dr)
+}
+
+// Event ...
+// snippet event.doc
+// For create action for widget
+// end event.doc
+func (cm *ContextMenu) Event(ev tcell.Event) {
+	if cm.root == nil {
+		return
+	}
+	if button, ok := cm.onFocus(ev); ok && button[2] {
+		cm.request = true
+	}
+	cm.root.Event(ev)
+}
 
 ///////////////////////////////////////////////////////////////////////////////
 
@@ -3111,6 +8402,7 @@ type Tree struct {
 // Draw widget inside window with width `width` used Drawer style `dr` and return height of widget.
 // end render.doc
 func (tr *Tree) Render(width uint, dr Drawer) (height uint) {
+	theme := tr.theme()
 	defer func() {
 		tr.StoreSize(width, height)
 	}()
@@ -3121,7 +8413,7 @@ func (tr *Tree) Render(width uint, dr Drawer) (height uint) {
 	}
 
 	if w := tr.Root; w != nil {
-		height = w.Render(width, dr)
+		height = tr.pass(w).Render(width, dr)
 	}
 	tr.offsetRoot.row = 0
 	tr.offsetRoot.col = 0
@@ -3140,7 +8432,7 @@ func (tr *Tree) Render(width uint, dr Drawer) (height uint) {
 		}
 		tr.offsetNodes[i].col = 2
 		tr.offsetNodes[i].row = height
-		h := tr.Nodes[i].Render(width-2, draw)
+		h := tr.pass(&tr.Nodes[i]).Render(width-2, draw)
 		height += h
 		hs = append(hs, height)
 	}
@@ -3150,15 +8442,15 @@ func (tr *Tree) Render(width uint, dr Drawer) (height uint) {
 		}
 		if 0 < i {
 			for h := hs[i-1] + 1; h < hs[i]; h++ {
-				dr(h, 0, TextStyle, LineVerticalUnfocus)
+				dr(h, 0, theme.TextStyle, theme.LineVerticalUnfocus)
 			}
 		}
 		if i == len(hs)-1-1 {
-			dr(hs[i], 0, TextStyle, TreeUp)
+			dr(hs[i], 0, theme.TextStyle, theme.TreeUp)
 		} else {
-			dr(hs[i], 0, TextStyle, TreeUpDown)
+			dr(hs[i], 0, theme.TextStyle, theme.TreeUpDown)
 		}
-		dr(hs[i], 1, TextStyle, LineHorizontalUnfocus)
+		dr(hs[i], 1, theme.TextStyle, theme.LineHorizontalUnfocus)
 	}
 	if 1 < len(hs) {
 		height++
@@ -3166,6 +8458,31 @@ func (tr *Tree) Render(width uint, dr Drawer) (height uint) {
 	return
 }
 
+// Focus ...
+// snippet focus.doc
+// For changing focus-state of widget
+// end focus.doc
+func (tr *Tree) Focus(focus bool) {
+	if !focus {
+		if w := tr.Root; w != nil {
+			w.Focus(focus)
+		}
+		for i := range tr.Nodes {
+			tr.Nodes[i].Focus(focus)
+		}
+	}
+	tr.container.Focus(focus)
+}
+
+// Children return internal widgets in focus order
+func (tr *Tree) Children() []Widget {
+	ws := []Widget{tr.Root}
+	for i := range tr.Nodes {
+		ws = append(ws, &tr.Nodes[i])
+	}
+	return ws
+}
+
 // Event ...
 // snippet event.doc
 // For create action for widget
@@ -3190,7 +8507,9 @@ func (tr *Tree) Event(ev tcell.Event) {
 				ev.Modifiers()))
 
 		case *tcell.EventKey:
-			tr.Root.Event(ev)
+			if isFocused(tr.Root) {
+				tr.Root.Event(ev)
+			}
 		}
 	}
 	for i := range tr.Nodes {
@@ -3205,7 +8524,9 @@ func (tr *Tree) Event(ev tcell.Event) {
 				ev.Modifiers()))
 
 		case *tcell.EventKey:
-			tr.Nodes[i].Event(ev)
+			if tr.Nodes[i].focus {
+				tr.Nodes[i].Event(ev)
+			}
 		}
 	}
 }
@@ -3609,9 +8930,31 @@ According to Bandler and Grinder our chosen words, phrases and sentences are ind
 ///////////////////////////////////////////////////////////////////////////////
 
 type container struct {
+	scoped
 	focus  bool
 	width  uint
 	height uint
+	dirty  atomic.Bool
+}
+
+// MarkDirty mark widget and screen of widget as changed. Application
+// redraw screen with changed widgets without events. Function is safe
+// for use from other goroutines. Widgets of other packages call
+// MarkDirty of Screen.
+func (c *container) MarkDirty() {
+	c.dirty.Store(true)
+	if screen := c.owner.Load(); screen != nil {
+		screen.MarkDirty()
+	}
+}
+
+// IsDirty return true if widget is changed after last drawing
+func (c *container) IsDirty() bool {
+	return c.dirty.Load()
+}
+
+func (c *container) clearDirty() {
+	c.dirty.Store(false)
 }
 
 // Focus ...
@@ -3622,6 +8965,11 @@ func (c *container) Focus(focus bool) {
 	c.focus = focus
 }
 
+// IsFocused return focus-state of widget
+func (c *container) IsFocused() bool {
+	return c.focus
+}
+
 // StoreSize ...
 // snippet storesize.doc
 // For storing widget sizes.
@@ -3635,7 +8983,7 @@ func (c *container) StoreSize(width, height uint) {
 // snippet getsize.doc
 // return for widget sizes
 // end getsize.doc
-func (c container) GetSize() (width, height uint) {
+func (c *container) GetSize() (width, height uint) {
 	return c.width, c.height
 }
 
@@ -3713,10 +9061,12 @@ type Compressable interface {
 
 ///////////////////////////////////////////////////////////////////////////////
 
+// TimeFrameSleep is maximal time between checks of dirty widgets
+// without events, actions and calls of MarkDirty
 var TimeFrameSleep time.Duration
 
 func init() {
-	// Sleep between frames updates
+	// Sleep between checks of dirty widgets
 	if TimeFrameSleep <= 0 {
 		TimeFrameSleep = time.Second * 5
 	}
@@ -3724,152 +9074,337 @@ func init() {
 
 var debugs []string
 
-var (
-	screen     tcell.Screen
-	simulation bool
-)
+// App is terminal application with root widget.
+// Zero value is ready for use:
+//
+//	var app App
+//	app.SetRoot(root)
+//	app.SetQuitKeys(tcell.KeyCtrlC)
+//	err := app.Run()
+type App struct {
+	rootable
 
-func Run(root Widget, action chan func(), chQuit <-chan struct{}, quitKeys ...tcell.Key) (err error) {
+	screen   tcell.Screen
+	quitKeys []tcell.Key
+	fps      uint // maximal frame rate
+
+	once    sync.Once
+	actions chan func()   // queue of actions
+	quit    chan struct{} // closed by Stop
+	wake    chan struct{} // wakeup by MarkDirty of widgets
+	stop    sync.Once
+}
+
+// init prepare internal channels
+func (app *App) init() {
+	app.once.Do(func() {
+		app.actions = make(chan func(), 100)
+		app.quit = make(chan struct{})
+		app.wake = make(chan struct{}, 1)
+	})
+}
+
+// SetScreen set terminal screen of application, for example
+// tcell.SimulationScreen for tests. If screen is not set, then
+// terminal screen is created by Run.
+func (app *App) SetScreen(screen tcell.Screen) {
+	app.screen = screen
+}
+
+// GetScreen return terminal screen of application
+func (app *App) GetScreen() tcell.Screen {
+	return app.screen
+}
+
+// DefaultFrameRate is maximal amount of frames per second by default
+var DefaultFrameRate uint = 30
+
+// SetFrameRate set maximal amount of frames per second.
+// All events and actions between frames are drawn in one frame.
+func (app *App) SetFrameRate(fps uint) {
+	app.fps = fps
+}
+
+// frameTime return minimal duration between frames
+func (app *App) frameTime() time.Duration {
+	fps := app.fps
+	if fps == 0 {
+		fps = DefaultFrameRate
+	}
+	if fps == 0 {
+		return 0
+	}
+	return time.Second / time.Duration(fps)
+}
+
+// SetQuitKeys set keys for stopping of application
+func (app *App) SetQuitKeys(keys ...tcell.Key) {
+	app.quitKeys = keys
+}
+
+// QueueUpdate add function in queue of actions. Functions are run
+// in goroutine of Run between events, so widgets may be changed
+// without data race.
+func (app *App) QueueUpdate(f func()) {
+	if f == nil {
+		return
+	}
+	app.init()
+	select {
+	case app.actions <- f:
+	case <-app.quit:
+	}
+}
+
+// Stop application. Function Run return after that.
+func (app *App) Stop() {
+	app.init()
+	app.stop.Do(func() {
+		close(app.quit)
+	})
+}
+
+// listen forward functions from channel `action` to queue of actions
+// and stop application after receiving or closing of channel `chQuit`.
+// Return function for stopping of listening.
+func (app *App) listen(action chan func(), chQuit <-chan struct{}) (done func()) {
+	app.init()
+	ch := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case f, ok := <-action:
+				if !ok {
+					action = nil
+					break
+				}
+				app.QueueUpdate(f)
+			case <-chQuit:
+				app.Stop()
+				return
+			case <-ch:
+				return
+			case <-app.quit:
+				return
+			}
+		}
+	}()
+	return func() { close(ch) }
+}
+
+// Run application until quit key or Stop
+func (app *App) Run() (err error) {
 	defer func() {
 		for i := range debugs {
 			fmt.Println(i, ":", debugs[i])
 		}
 	}()
 
+	root := app.root
 	if root == nil {
 		err = fmt.Errorf("root widget is nil")
 		return
 	}
+	app.init()
 
 	tcell.SetEncodingFallback(tcell.EncodingFallbackUTF8)
-	if simulation {
-		screen = tcell.NewSimulationScreen("")
-	} else {
-		if screen, err = tcell.NewScreen(); err != nil {
+	if app.screen == nil {
+		if app.screen, err = tcell.NewScreen(); err != nil {
 			return
 		}
 	}
+	screen := app.screen
 	if err = screen.Init(); err != nil {
 		return
 	}
 
-	screen.EnableMouse(tcell.MouseButtonEvents) // Click event only
-	screen.EnablePaste()                        // ?
-	screen.SetStyle(ScreenStyle)
-	screen.Clear()
+	screen.EnableMouse(tcell.MouseButtonEvents | tcell.MouseDragEvents)
+	screen.EnablePaste() // pasted text is collected by Screen
 
 	defer func() {
 		screen.Fini()
 	}()
 
-	var mu sync.Mutex
-	var quit bool
-
 	// event actions
 	chEvent := make(chan tcell.Event, 1)
 	go func() {
 		for {
-			if quit {
-				break
+			ev := screen.PollEvent()
+			if ev == nil {
+				// screen is finalized
+				return
+			}
+			select {
+			case chEvent <- ev:
+			case <-app.quit:
+				return
 			}
-			chEvent <- screen.PollEvent()
 		}
 	}()
+	defer app.Stop()
+
+	// screen is used for keyboard focus traversal
+	sc, ok := root.(*Screen)
+	if !ok {
+		sc = new(Screen)
+		sc.SetRoot(root)
+	}
+	// widgets wake up application by MarkDirty
+	sc.wake.Store(&app.wake)
+	defer sc.wake.Store(nil)
+	screen.SetStyle(sc.GetTheme().ScreenStyle)
+	screen.Clear()
 
-	if sc, ok := root.(*Screen); ok {
-		sc.Fill(screen.Fill)
-	}
-
-	var ignore bool
-	for {
-		if quit {
-			break
+	var (
+		quit  bool
+		dirty = true // first frame is always drawn
+		last  time.Time
+		frame = app.frameTime()
+		// current and previous frames
+		cells, prev [][]Cell
+		// pressed mouse buttons
+		buttons tcell.ButtonMask
+	)
+	// fill background of terminal at full redraw and background of frame
+	defer sc.Fill(sc.fill)
+	sc.Fill(func(r rune, s tcell.Style) {
+		if prev == nil {
+			screen.Fill(r, s)
+		}
+		for row := range cells {
+			for col := range cells[row] {
+				cells[row][col] = Cell{S: s, R: r}
+			}
 		}
-
-		ignore = false
-
-		select {
-		case ev := <-chEvent:
-			switch ev := ev.(type) {
-			case *tcell.EventResize:
-				screen.Sync()
-			case *tcell.EventKey:
-				for i := range quitKeys {
-					if quitKeys[i] == ev.Key() {
-						quit = true
-						break
-					}
+	})
+	// event return true if screen may be changed
+	event := func(ev tcell.Event) bool {
+		switch ev := ev.(type) {
+		case *tcell.EventResize:
+			screen.Sync()
+			prev = nil // redraw all cells
+			sc.Event(ev)
+			return true
+		case *tcell.EventKey:
+			for i := range app.quitKeys {
+				if app.quitKeys[i] == ev.Key() {
+					quit = true
+					return false
 				}
-			case *tcell.EventMouse:
+			}
+		case *tcell.EventMouse:
+			pressed := buttons
+			buttons = ev.Buttons() & mouseButtons
+			if sc.drag == nil {
 				if ev.Buttons() == tcell.ButtonNone {
-					ignore = true
+					return false
+				}
+				if buttons != 0 && buttons == pressed {
+					// ignore motion with pressed button
+					return false
 				}
 			}
-			if quit {
-				break
-			}
-			if ev != nil { // Always && root != nil {
-				mu.Lock()
-				if runtime.GOOS == "windows" {
-					if p, ok := ev.(*tcell.EventMouse); ok {
-						bm := p.Buttons()
-						if bm == tcell.Button1 || bm == tcell.Button2 || bm == tcell.Button3 {
-							time.Sleep(time.Millisecond * 500) // sleep for Windows
-						}
-					}
+			if runtime.GOOS == "windows" && sc.drag == nil {
+				bm := ev.Buttons()
+				if bm == tcell.Button1 || bm == tcell.Button2 || bm == tcell.Button3 {
+					time.Sleep(time.Millisecond * 500) // sleep for Windows
 				}
-				root.Event(ev)
-				mu.Unlock()
 			}
-		case <-time.After(TimeFrameSleep):
-			// time sleep beween frames
-			// do nothing
+		}
+		sc.Event(ev)
+		return sc.IsDirty()
+	}
 
-		case <-chQuit:
+	for !quit {
+		// wait events, actions and changes of widgets or next frame
+		// for drawing of changes
+		wait := TimeFrameSleep
+		if dirty {
+			wait = frame - time.Since(last)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case ev := <-chEvent:
+			dirty = event(ev) || dirty
+		case f := <-app.actions:
+			f()
+			dirty = true
+		case <-app.quit:
 			quit = true
-		case f := <-action:
-			if f == nil {
-				// do nothing
-				continue
+		case <-app.wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+		// coalesce all ready events and actions
+		for coalesce := true; coalesce && !quit; {
+			select {
+			case ev := <-chEvent:
+				dirty = event(ev) || dirty
+			case f := <-app.actions:
+				f()
+				dirty = true
+			default:
+				coalesce = false
 			}
-			// default action
-			f()
 		}
-		// render
-
 		if quit {
 			break
 		}
-		if ignore {
+		dirty = dirty || sc.IsDirty()
+		if !dirty || time.Since(last) < frame {
 			continue
 		}
-
-		// clear screen
-		mu.Lock()
-		screen.Clear()
-		// draw root widget
-		if width, height := screen.Size(); 0 < width && 0 < height {
-			const widthOffset uint = 1 // for avoid terminal collisions
-			// root wigdets
-			if vf, ok := root.(VerticalFix); ok {
-				vf.SetHeight(uint(height))
-			}
-			// ignore height of root widget height
-			_ = root.Render(uint(width)-widthOffset,
-				func(row, col uint, st tcell.Style, r rune) {
-					// row , col always positive or zero
-					if uint(height) < row {
-						return
-					}
-					if uint(width) < col {
-						return
-					}
-					screen.SetCell(int(col), int(row), st, r)
-				})
+		// render
+		dirty = false
+		last = time.Now()
+		width, height := screen.Size()
+		if width <= 0 || height <= 0 {
+			continue
+		}
+		const widthOffset = 1 // for avoid terminal collisions
+		// widgets marked dirty at rendering are drawn at next frame
+		walk(sc, func(w Widget) {
+			if d, ok := w.(interface{ clearDirty() }); ok {
+				d.clearDirty()
+			}
+		})
+		sc.SetHeight(uint(height))
+		sc.GetContents(uint(width-widthOffset), &cells)
+		// draw only changed cells
+		changed := false
+		for row := range cells {
+			for col := range cells[row] {
+				c := cells[row][col]
+				if row < len(prev) && col < len(prev[row]) && prev[row][col] == c {
+					continue
+				}
+				screen.SetContent(col, row, c.R, nil, c.S)
+				changed = true
+			}
 		}
+		cells, prev = prev, cells
 		// show screen result
-		screen.Show()
-		mu.Unlock()
+		if changed {
+			screen.Show()
+		}
 	}
 	return
 }
+
+// Run widget `root` in terminal. Functions from channel `action` are
+// run between events. Application is stopped by channel `chQuit` or
+// by one of keys `quitKeys`.
+func Run(root Widget, action chan func(), chQuit <-chan struct{}, quitKeys ...tcell.Key) (err error) {
+	return run(nil, root, action, chQuit, quitKeys...)
+}
+
+// run is Run with terminal screen `screen`. If screen is nil, then
+// terminal screen is created.
+func run(screen tcell.Screen, root Widget, action chan func(), chQuit <-chan struct{}, quitKeys ...tcell.Key) (err error) {
+	var app App
+	app.SetScreen(screen)
+	app.SetRoot(root)
+	app.SetQuitKeys(quitKeys...)
+	defer app.listen(action, chQuit)()
+	return app.Run()
+}
@@ -3,11 +3,15 @@ package vl
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime/debug"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 	"time"
 	"unicode/utf8"
 
@@ -136,7 +140,7 @@ func Test(t *testing.T) {
 			// }()
 			var screen Screen
 			screen.SetRoot(rt)
-			check(t, name, si, screen)
+			check(t, name, si, &screen)
 		})
 	}
 	for si := range sizes {
@@ -146,7 +150,7 @@ func Test(t *testing.T) {
 	}
 }
 
-func check(t *testing.T, name string, si int, screen Screen) {
+func check(t *testing.T, name string, si int, screen *Screen) {
 	width := sizes[si]
 	height := sizes[si]
 
@@ -244,18 +248,15 @@ func check(t *testing.T, name string, si int, screen Screen) {
 }
 
 func TestRun(t *testing.T) {
-	simulation = true
-	defer func() {
-		simulation = false
-	}()
 	t.Run("exit by key", func(t *testing.T) {
+		sim := tcell.NewSimulationScreen("")
 		action := make(chan func(), 10)
 		root := Demo()[0]
 		go func() {
 			<-time.After(time.Millisecond * 200)
-			screen.(tcell.SimulationScreen).InjectKey(tcell.KeyCtrlC, ' ', tcell.ModNone)
+			sim.InjectKey(tcell.KeyCtrlC, ' ', tcell.ModNone)
 		}()
-		err := Run(root, action, nil, tcell.KeyCtrlC)
+		err := run(sim, root, action, nil, tcell.KeyCtrlC)
 		if err != nil {
 			t.Fatalf("%v", err)
 		}
@@ -269,7 +270,7 @@ func TestRun(t *testing.T) {
 			var closed struct{}
 			qu <- closed
 		}()
-		err := Run(root, action, qu)
+		err := run(tcell.NewSimulationScreen(""), root, action, qu)
 		if err != nil {
 			t.Fatalf("%v", err)
 		}
@@ -282,13 +283,226 @@ func TestRun(t *testing.T) {
 			<-time.After(time.Millisecond * 200)
 			close(qu)
 		}()
-		err := Run(root, action, qu)
+		err := run(tcell.NewSimulationScreen(""), root, action, qu)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+	})
+	t.Run("exit by stop", func(t *testing.T) {
+		sim := tcell.NewSimulationScreen("")
+		sim.SetSize(30, 5)
+		var (
+			app  App
+			text Text
+		)
+		app.SetScreen(sim)
+		app.SetRoot(&text)
+		go func() {
+			app.QueueUpdate(func() { text.SetText("Updated") })
+			app.QueueUpdate(app.Stop)
+		}()
+		err := app.Run()
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if text.GetText() != "Updated" {
+			t.Errorf("action is not run")
+		}
+	})
+	t.Run("coalesce events", func(t *testing.T) {
+		sim := tcell.NewSimulationScreen("")
+		var (
+			app     App
+			counter renderCounter
+		)
+		app.SetScreen(sim)
+		app.SetRoot(&counter)
+		app.SetFrameRate(1)
+		go func() {
+			counter.wait(t)
+			for i := 0; i < 20; i++ {
+				app.QueueUpdate(func() {})
+			}
+			app.QueueUpdate(app.Stop)
+		}()
+		err := app.Run()
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if counter.renders != 1 {
+			t.Errorf("not valid amount of frames: %d", counter.renders)
+		}
+	})
+	t.Run("events without changes", func(t *testing.T) {
+		sim := tcell.NewSimulationScreen("")
+		var (
+			app     App
+			screen  Screen
+			counter renderCounter
+		)
+		screen.SetRoot(&counter)
+		app.SetScreen(sim)
+		app.SetRoot(&screen)
+		app.SetQuitKeys(tcell.KeyCtrlC)
+		go func() {
+			counter.wait(t)
+			for i := 0; i < 20; i++ {
+				sim.PostEventWait(tcell.NewEventKey(tcell.KeyRune, 'W', tcell.ModNone))
+				sim.PostEventWait(tcell.NewEventMouse(1, 1, tcell.Button1, tcell.ModNone))
+				sim.PostEventWait(tcell.NewEventMouse(1, 1, tcell.ButtonNone, tcell.ModNone))
+			}
+			// events are handled in order, so all events are handled
+			// before quit key
+			sim.PostEventWait(tcell.NewEventKey(tcell.KeyCtrlC, ' ', tcell.ModNone))
+		}()
+		err := app.Run()
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if screen.IsDirty() {
+			t.Errorf("screen is dirty")
+		}
+		if counter.renders != 1 {
+			t.Errorf("not valid amount of frames: %d", counter.renders)
+		}
+	})
+	t.Run("mark dirty", func(t *testing.T) {
+		// application is woken up by MarkDirty without timer
+		sleep := TimeFrameSleep
+		TimeFrameSleep = time.Hour
+		defer func() { TimeFrameSleep = sleep }()
+		sim := tcell.NewSimulationScreen("")
+		var (
+			app     App
+			counter renderCounter
+		)
+		app.SetScreen(sim)
+		app.SetRoot(&counter)
+		go func() {
+			counter.wait(t)
+			counter.MarkDirty()
+			counter.wait(t)
+			app.Stop()
+		}()
+		err := app.Run()
 		if err != nil {
 			t.Fatalf("%v", err)
 		}
+		if counter.renders != 2 {
+			t.Errorf("not valid amount of frames: %d", counter.renders)
+		}
+	})
+	t.Run("drag", func(t *testing.T) {
+		sim := tcell.NewSimulationScreen("")
+		sim.SetSize(30, 5)
+		var (
+			app     App
+			button  Button
+			counter renderCounter
+			clicks  int
+		)
+		button.SetText("Button")
+		button.OnClick = func() { clicks++ }
+		counter.root = &button
+		app.SetScreen(sim)
+		app.SetRoot(&counter)
+		app.SetQuitKeys(tcell.KeyCtrlC)
+		go func() {
+			counter.wait(t)
+			for i := 0; i < 3; i++ {
+				sim.PostEventWait(tcell.NewEventMouse(2+i, 0, tcell.Button1, tcell.ModNone))
+			}
+			sim.PostEventWait(tcell.NewEventMouse(5, 0, tcell.ButtonNone, tcell.ModNone))
+			sim.PostEventWait(tcell.NewEventMouse(2, 0, tcell.Button1, tcell.ModNone))
+			sim.PostEventWait(tcell.NewEventKey(tcell.KeyCtrlC, ' ', tcell.ModNone))
+		}()
+		err := app.Run()
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if clicks != 2 {
+			t.Errorf("not valid amount of clicks: %d", clicks)
+		}
+	})
+	t.Run("nil root", func(t *testing.T) {
+		var app App
+		app.SetScreen(tcell.NewSimulationScreen(""))
+		if err := app.Run(); err == nil {
+			t.Errorf("error is not found")
+		}
 	})
 }
 
+// renderCounter count frames and draw optional root widget
+type renderCounter struct {
+	container
+	root    Widget
+	renders int
+	frames  chan struct{}
+	once    sync.Once
+}
+
+func (r *renderCounter) init() {
+	r.once.Do(func() { r.frames = make(chan struct{}, 100) })
+}
+
+func (r *renderCounter) Render(width uint, dr Drawer) (height uint) {
+	r.init()
+	r.renders++
+	defer func() { r.frames <- struct{}{} }()
+	if r.root == nil {
+		return 1
+	}
+	height = r.pass(r.root).Render(width, dr)
+	r.StoreSize(width, height)
+	return
+}
+
+func (r *renderCounter) Event(ev tcell.Event) {
+	if r.root != nil {
+		r.root.Event(ev)
+	}
+}
+
+// wait next frame
+func (r *renderCounter) wait(t *testing.T) {
+	r.init()
+	select {
+	case <-r.frames:
+	case <-time.After(10 * time.Second):
+		t.Errorf("frame is not drawn")
+	}
+}
+
+func TestDirty(t *testing.T) {
+	var (
+		list   List
+		text   Text
+		button Button
+		screen Screen
+	)
+	list.Add(&text)
+	list.Add(&button)
+	screen.SetRoot(&list)
+	screen.SetHeight(2)
+	cells := new([][]Cell)
+	screen.GetContents(10, cells)
+	if screen.IsDirty() {
+		t.Errorf("screen is dirty")
+	}
+	button.MarkDirty()
+	if !button.IsDirty() {
+		t.Errorf("widget is not dirty")
+	}
+	if !screen.IsDirty() {
+		t.Errorf("dirty widget does not mark screen")
+	}
+	button.clearDirty()
+	if button.IsDirty() {
+		t.Errorf("widget is dirty after clearing")
+	}
+}
+
 // goos: linux
 // goarch: amd64
 // pkg: github.com/Konstantin8105/vl
@@ -663,6 +877,30 @@ func list() []Widget {
 			c.BorderIfClosed(false)
 			return c
 		}(),
+		func() Widget {
+			t := new(Table)
+			t.SetColumns(
+				TableColumn{Name: "Name"},
+				TableColumn{Name: "Value", Policy: ColumnWeight},
+			)
+			t.AddRow("one", "1")
+			t.AddRow("two", "2")
+			return t
+		}(),
+		new(SplitPane),
+		func() Widget {
+			sp := new(SplitPane)
+			sp.Horizontal = true
+			sp.SetFirst(TextStatic("Hello"))
+			sp.SetSecond(TextStatic("World"))
+			return sp
+		}(),
+		func() Widget {
+			sb := new(SpinBox)
+			sb.Precision = 2
+			sb.SetValue(12.5)
+			return sb
+		}(),
 	}
 }
 
@@ -686,6 +924,9 @@ func getName(w Widget) string {
 			name += "NoBorder"
 		}
 	}
+	if sp, ok := w.(*SplitPane); ok && sp.Horizontal {
+		name += "Horizontal"
+	}
 	return name
 }
 
@@ -886,6 +1127,9 @@ func TestWidget(t *testing.T) {
 						int(col), int(row),
 						tcell.Button1, tcell.ModNone)
 					screen.Event(click)
+					screen.Event(tcell.NewEventMouse(
+						int(col), int(row),
+						tcell.ButtonNone, tcell.ModNone))
 					screen.GetContents(width, cells)
 					if int(row) < len(*cells) {
 						if int(col) < len((*cells)[row]) {
@@ -1193,3 +1437,1980 @@ According to Bandler and Grinder our chosen words, phrases and sentences are ind
 func TestSnippet(t *testing.T) {
 	snippet.Test(t, ".")
 }
+
+func TestFocus(t *testing.T) {
+	var (
+		list  List
+		frame Frame
+		inner List
+		b     Button
+		ch    CheckBox
+		in    InputBox
+		rg    RadioGroup
+	)
+	var clicks int
+	b.SetText("Button")
+	b.OnClick = func() { clicks++ }
+	ch.SetText("CheckBox")
+	in.SetText("Input")
+	rg.AddText("one", "two")
+	list.Add(TextStatic("Focus test"))
+	list.Add(&b)
+	frame.Header = TextStatic("Frame")
+	inner.Add(&ch)
+	inner.Add(&in)
+	frame.SetRoot(&inner)
+	list.Add(&frame)
+	list.Add(&rg)
+
+	var scroll Scroll
+	scroll.SetRoot(&list)
+
+	var screen Screen
+	screen.SetRoot(&scroll)
+	screen.SetHeight(14)
+
+	var buf bytes.Buffer
+	cells := new([][]Cell)
+	for _, ev := range []struct {
+		name string
+		ev   tcell.Event
+	}{
+		{"none", nil},
+		{"Tab", tcell.NewEventKey(tcell.KeyTab, ' ', tcell.ModNone)},
+		{"Enter", tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone)},
+		{"Tab", tcell.NewEventKey(tcell.KeyTab, ' ', tcell.ModNone)},
+		{"Space", tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone)},
+		{"Tab", tcell.NewEventKey(tcell.KeyTab, ' ', tcell.ModNone)},
+		{"InputRune", tcell.NewEventKey(tcell.KeyRune, 'W', tcell.ModNone)},
+		{"Tab", tcell.NewEventKey(tcell.KeyTab, ' ', tcell.ModNone)},
+		{"Tab", tcell.NewEventKey(tcell.KeyTab, ' ', tcell.ModNone)},
+		{"Enter", tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone)},
+		{"Tab", tcell.NewEventKey(tcell.KeyTab, ' ', tcell.ModNone)},
+		{"Backtab", tcell.NewEventKey(tcell.KeyBacktab, ' ', tcell.ModNone)},
+		{"Backtab", tcell.NewEventKey(tcell.KeyBacktab, ' ', tcell.ModNone)},
+		{"ClickText", tcell.NewEventMouse(1, 0, tcell.Button1, tcell.ModNone)},
+		{"ClickFrame", tcell.NewEventMouse(10, 3, tcell.Button1, tcell.ModNone)},
+	} {
+		last := screen.GetFocused()
+		if ev.ev != nil {
+			screen.Event(ev.ev)
+		}
+		if _, ok := ev.ev.(*tcell.EventMouse); ok && screen.GetFocused() != last {
+			// click on not focusable area keep focus of last focused widget
+			t.Errorf("%s: focus is changed", ev.name)
+		}
+		screen.GetContents(30, cells)
+		fmt.Fprintf(&buf, "Move: %s\n", ev.name)
+		fmt.Fprintf(&buf, "%s", Convert(*cells))
+
+		// only one focused leaf
+		var focused int
+		for _, path := range focusPaths(&list) {
+			if isFocused(path[len(path)-1]) {
+				focused++
+			}
+		}
+		if ev.ev != nil && focused != 1 {
+			t.Errorf("%s: not valid amount of focused widgets: %d", ev.name, focused)
+		}
+	}
+	if frame.focus || inner.focus {
+		t.Errorf("clicked frame is focused")
+	}
+	if clicks != 1 {
+		t.Errorf("not valid amount of clicks: %d", clicks)
+	}
+	if !ch.Checked {
+		t.Errorf("checkbox is not checked")
+	}
+	if rg.GetPos() != 1 {
+		t.Errorf("not valid radio position: %d", rg.GetPos())
+	}
+	if in.GetText() != "WInput" {
+		t.Errorf("not valid text: %s", in.GetText())
+	}
+
+	filename := filepath.Join(testdata, "Focus")
+	compare.Test(t, filename, buf.Bytes())
+}
+
+func TestDialog(t *testing.T) {
+	var (
+		list   List
+		before Button
+		screen Screen
+	)
+	var clicks int
+	before.SetText("Button behind dialog")
+	before.OnClick = func() { clicks++ }
+	list.Add(TextStatic("Root text"))
+	list.Add(&before)
+	screen.SetRoot(&list)
+	screen.SetHeight(12)
+
+	var (
+		content List
+		ok      Button
+	)
+	content.Add(TextStatic("Dialog text"))
+	ok.SetText("OK")
+	ok.Compress()
+	ok.OnClick = func() { screen.Close() }
+	content.Add(&ok)
+
+	var buf bytes.Buffer
+	cells := new([][]Cell)
+	for _, ev := range []struct {
+		name string
+		f    func()
+	}{
+		{"none", func() {}},
+		{"FocusRoot", func() { screen.FocusNext() }},
+		{"AddDialog", func() { screen.AddDialog("Dialog", &content) }},
+		{"ClickOutside", func() {
+			screen.Event(tcell.NewEventMouse(1, 4, tcell.Button1, tcell.ModNone))
+		}},
+		{"Tab", func() {
+			screen.Event(tcell.NewEventKey(tcell.KeyTab, ' ', tcell.ModNone))
+		}},
+		{"Enter", func() {
+			screen.Event(tcell.NewEventKey(tcell.KeyEnter, ' ', tcell.ModNone))
+		}},
+		{"AddDialog", func() { screen.AddDialog("Dialog", &content) }},
+		{"Escape", func() {
+			screen.Event(tcell.NewEventKey(tcell.KeyEscape, ' ', tcell.ModNone))
+		}},
+	} {
+		ev.f()
+		screen.GetContents(30, cells)
+		fmt.Fprintf(&buf, "Move: %s\n", ev.name)
+		fmt.Fprintf(&buf, "%s", Convert(*cells))
+	}
+	if clicks != 0 {
+		t.Errorf("event is not trapped by dialog")
+	}
+	if screen.GetFocused() != &before {
+		t.Errorf("focus is not restored")
+	}
+
+	filename := filepath.Join(testdata, "Dialog")
+	compare.Test(t, filename, buf.Bytes())
+}
+
+func TestDialogEscape(t *testing.T) {
+	var (
+		root   Button
+		screen Screen
+		v      Viewer
+	)
+	root.SetText("Root")
+	screen.SetRoot(&root)
+	screen.SetHeight(10)
+	v.SetText("Text inside dialog")
+	screen.AddDialog("Dialog", &v)
+	cells := new([][]Cell)
+	screen.GetContents(30, cells)
+	key := func(k tcell.Key, r rune) {
+		screen.Event(tcell.NewEventKey(k, r, tcell.ModNone))
+		screen.GetContents(30, cells)
+	}
+	key(tcell.KeyRune, '/')
+	key(tcell.KeyRune, 'x')
+	// escape is used by search of viewer
+	key(tcell.KeyEscape, 0)
+	if len(screen.dialogs) != 1 {
+		t.Fatalf("dialog is closed by escape of search")
+	}
+	if v.search.input {
+		t.Errorf("search is not stopped")
+	}
+	key(tcell.KeyEscape, 0)
+	if len(screen.dialogs) != 0 {
+		t.Errorf("dialog is not closed")
+	}
+}
+
+func TestDialogSize(t *testing.T) {
+	var (
+		root   Button
+		screen Screen
+		fd     FileDialog
+	)
+	root.SetText("Root")
+	screen.SetRoot(&root)
+	screen.SetHeight(20)
+	fd.SetFS(fstest.MapFS{"readme.md": {Data: []byte("readme")}})
+	fd.SetListHeight(3)
+	screen.AddDialog("Open", &fd)
+	screen.SetDialogWidth(20)
+	cells := new([][]Cell)
+	screen.GetContents(40, cells)
+	if width, _ := screen.dialogs[0].frame.GetSize(); width != 20 {
+		t.Errorf("not valid width of dialog: %d", width)
+	}
+	if _, height := fd.view.GetSize(); height != 3 {
+		t.Errorf("not valid height of file list: %d", height)
+	}
+	screen.SetDialogWidth(0)
+	screen.GetContents(80, cells)
+	if width, _ := screen.dialogs[0].frame.GetSize(); width != dialogWidth {
+		t.Errorf("not valid default width of dialog: %d", width)
+	}
+}
+
+func TestFileDialog(t *testing.T) {
+	fsys := fstest.MapFS{
+		"readme.md":           {Data: []byte("readme")},
+		"main.go":             {Data: []byte("package main")},
+		"docs/index.md":       {Data: []byte("index")},
+		"docs/images/a.png":   {Data: []byte("png")},
+		"docs/todo.md":        {Data: []byte("todo")},
+		"internal/lib/lib.go": {Data: []byte("package lib")},
+	}
+	var screen Screen
+	screen.SetHeight(20)
+
+	var fd FileDialog
+	fd.SetFS(fsys)
+	fd.SetFilter("*.md")
+	var selected []string
+	fd.OnSelect = func(path string) { selected = append(selected, path) }
+	screen.SetRoot(&fd)
+
+	var buf bytes.Buffer
+	cells := new([][]Cell)
+	view := func(name string) {
+		screen.GetContents(30, cells)
+		fmt.Fprintf(&buf, "Move: %s\n", name)
+		fmt.Fprintf(&buf, "%s", Convert(*cells))
+	}
+	click := func(text string) {
+		for row := range *cells {
+			var line string
+			for col := range (*cells)[row] {
+				line += string((*cells)[row][col].R)
+			}
+			if col := strings.Index(line, text); 0 <= col {
+				screen.Event(tcell.NewEventMouse(col, row, tcell.Button1, tcell.ModNone))
+				view("Click " + text)
+				return
+			}
+		}
+		t.Fatalf("cannot find text: %s", text)
+	}
+	key := func(k tcell.Key, r rune) {
+		screen.Event(tcell.NewEventKey(k, r, tcell.ModNone))
+		view(fmt.Sprintf("Key %q", r))
+	}
+
+	view("none")
+	click("[ Open")
+	click("docs/")
+	click("todo.md")
+	click("[ Open")
+	click("../")
+	click("readme")
+	key(tcell.KeyTab, ' ')
+	key(tcell.KeyRune, 'X')
+	key(tcell.KeyBackspace, ' ')
+	key(tcell.KeyEnter, ' ')
+
+	fd.SetMode(FileSave)
+	fd.SetFileName("readme.md")
+	view("Save")
+	click("[ Save")
+	click("[ Save")
+	fd.SetFileName("new.md")
+	click("[ Save")
+	fd.SetFileName("docs")
+	click("[ Save")
+
+	expect := []string{"docs/todo.md", "readme.md", "readme.md", "new.md"}
+	if fmt.Sprint(selected) != fmt.Sprint(expect) {
+		t.Errorf("not valid selection: %v", selected)
+	}
+
+	filename := filepath.Join(testdata, "FileDialog")
+	compare.Test(t, filename, buf.Bytes())
+}
+
+func TestFileDialogConfirm(t *testing.T) {
+	var fd FileDialog
+	fd.SetFS(fstest.MapFS{
+		"a.txt":     {Data: []byte("a")},
+		"sub/a.txt": {Data: []byte("sub")},
+	})
+	fd.SetMode(FileSave)
+	var selected []string
+	fd.OnSelect = func(path string) { selected = append(selected, path) }
+	fd.SetFileName("a.txt")
+	fd.accept() // confirmation of overwrite
+	fd.SetDir("sub")
+	fd.accept() // other file with same name
+	if len(selected) != 0 {
+		t.Fatalf("file is overwritten without confirmation: %v", selected)
+	}
+	fd.accept()
+	if fmt.Sprint(selected) != "[sub/a.txt]" {
+		t.Errorf("not valid selection: %v", selected)
+	}
+}
+
+func TestFileDialogSaveAs(t *testing.T) {
+	var fd FileDialog
+	fd.SetFS(fstest.MapFS{
+		"a.txt": {Data: []byte("a")},
+	})
+	fd.SetMode(FileSaveAs)
+	var selected []string
+	fd.OnSelect = func(path string) { selected = append(selected, path) }
+	fd.prepare()
+	fd.SetFileName("a.txt")
+	fd.accept()
+	fd.accept() // exist file is not overwritten
+	if len(selected) != 0 {
+		t.Fatalf("exist file is choosed: %v", selected)
+	}
+	if s := fd.info.GetText(); s != `file "a.txt" is exist` {
+		t.Errorf("not valid info: %q", s)
+	}
+	fd.SetFileName("b.txt")
+	fd.accept()
+	if fmt.Sprint(selected) != "[b.txt]" {
+		t.Errorf("not valid selection: %v", selected)
+	}
+}
+
+func TestTable(t *testing.T) {
+	var table Table
+	table.SetColumns(
+		TableColumn{Name: "Name", Policy: ColumnFit},
+		TableColumn{Name: "Size", Policy: ColumnFixed, Value: 3},
+		TableColumn{Name: "Comment", Policy: ColumnWeight, Value: 1},
+	)
+	for i := 0; i < 20; i++ {
+		table.AddRow(
+			fmt.Sprintf("file%02d.go", i),
+			fmt.Sprintf("%d", i*i*i),
+			strings.Repeat("comment ", i%4),
+		)
+	}
+	var changes int
+	table.OnChange = func() { changes++ }
+
+	var screen Screen
+	screen.SetRoot(&table)
+	screen.SetHeight(8)
+
+	var buf bytes.Buffer
+	cells := new([][]Cell)
+	for _, ev := range []struct {
+		name string
+		ev   tcell.Event
+	}{
+		{"none", nil},
+		{"Click", tcell.NewEventMouse(1, 3, tcell.Button1, tcell.ModNone)},
+		{"Down", tcell.NewEventKey(tcell.KeyDown, ' ', tcell.ModNone)},
+		{"PgDn", tcell.NewEventKey(tcell.KeyPgDn, ' ', tcell.ModNone)},
+		{"PgDn", tcell.NewEventKey(tcell.KeyPgDn, ' ', tcell.ModNone)},
+		{"End", tcell.NewEventKey(tcell.KeyEnd, ' ', tcell.ModNone)},
+		{"WheelUp", tcell.NewEventMouse(1, 3, tcell.WheelUp, tcell.ModNone)},
+		{"Up", tcell.NewEventKey(tcell.KeyUp, ' ', tcell.ModNone)},
+		{"Home", tcell.NewEventKey(tcell.KeyHome, ' ', tcell.ModNone)},
+	} {
+		if ev.ev != nil {
+			screen.Event(ev.ev)
+		}
+		for _, width := range []uint{15, 40} {
+			screen.GetContents(width, cells)
+			fmt.Fprintf(&buf, "Move: %s\n", ev.name)
+			fmt.Fprintf(&buf, "%s", Convert(*cells))
+		}
+	}
+	if table.GetSelected() != 0 {
+		t.Errorf("not valid selected row: %d", table.GetSelected())
+	}
+	if changes != 7 {
+		t.Errorf("not valid amount of changes: %d", changes)
+	}
+
+	filename := filepath.Join(testdata, "Table")
+	compare.Test(t, filename, buf.Bytes())
+}
+
+func TestTableAddRow(t *testing.T) {
+	var table Table
+	row := []string{"first", "multi\nline"}
+	table.AddRow(row...)
+	row[0] = "second"
+	table.AddRow(row...)
+	if s := row[1]; s != "multi\nline" {
+		t.Errorf("cells of caller are changed: %q", s)
+	}
+	if s := table.GetRow(0)[0]; s != "first" {
+		t.Errorf("not valid first row: %q", s)
+	}
+	if s := table.GetRow(1)[1]; s != "multi line" {
+		t.Errorf("not valid second row: %q", s)
+	}
+	table.GetRow(0)[0] = "changed"
+	if s := table.GetRow(0)[0]; s != "first" {
+		t.Errorf("row is changed by caller: %q", s)
+	}
+}
+
+func TestContextMenu(t *testing.T) {
+	var (
+		list   List
+		cm     ContextMenu
+		sub    Menu
+		screen Screen
+	)
+	var actions []string
+	list.Add(TextStatic("Text without context menu"))
+	cm.SetRoot(TextStatic("Text with context menu"))
+	cm.AddButton("Copy", func() { actions = append(actions, "Copy") })
+	cm.AddButton("Paste", func() { actions = append(actions, "Paste") })
+	sub.AddButton("Upper", func() { actions = append(actions, "Upper") })
+	cm.AddMenu("Case", &sub)
+	list.Add(&cm)
+	screen.SetRoot(&list)
+	screen.SetHeight(12)
+
+	mouse := func(col, row int, button tcell.ButtonMask) func() {
+		return func() {
+			screen.Event(tcell.NewEventMouse(col, row, button, tcell.ModNone))
+		}
+	}
+	var buf bytes.Buffer
+	cells := new([][]Cell)
+	for _, ev := range []struct {
+		name string
+		f    func()
+	}{
+		{"none", func() {}},
+		{"RightClickWithout", mouse(2, 0, tcell.Button2)},
+		{"RightClick", mouse(2, 6, tcell.Button2)},
+		{"ClickCopy", mouse(4, 7, tcell.Button1)},
+		{"RightClickCorner", mouse(25, 6, tcell.Button2)},
+		{"Escape", func() {
+			screen.Event(tcell.NewEventKey(tcell.KeyEscape, ' ', tcell.ModNone))
+		}},
+		{"RightClick", mouse(2, 6, tcell.Button2)},
+		{"OpenSubmenu", mouse(4, 9, tcell.Button1)},
+		{"ClickUpper", mouse(6, 9, tcell.Button1)},
+		{"RightClick", mouse(2, 6, tcell.Button2)},
+		{"ClickOutside", mouse(28, 1, tcell.Button1)},
+	} {
+		ev.f()
+		screen.GetContents(30, cells)
+		fmt.Fprintf(&buf, "Move: %s\n", ev.name)
+		fmt.Fprintf(&buf, "%s", Convert(*cells))
+	}
+	if fmt.Sprint(actions) != "[Copy Upper]" {
+		t.Errorf("not valid actions: %v", actions)
+	}
+	if cm.IsOpen() {
+		t.Errorf("context menu is not closed")
+	}
+
+	filename := filepath.Join(testdata, "ContextMenu")
+	compare.Test(t, filename, buf.Bytes())
+}
+
+func TestMenuKeyboard(t *testing.T) {
+	var (
+		menu   Menu
+		file   Menu
+		recent Menu
+		edit   Menu
+		input  InputBox
+		screen Screen
+	)
+	var actions []string
+	action := func(name string) func() {
+		return func() { actions = append(actions, name) }
+	}
+	file.AddButton("&Open", action("Open"))
+	recent.AddButton("&Last", action("Last"))
+	file.AddMenu("&Recent", &recent)
+	edit.AddButton("&Copy", action("Copy"))
+	edit.AddButton("&Paste", action("Paste"))
+	menu.AddMenu("&File", &file)
+	menu.AddMenu("&Edit", &edit)
+	menu.AddButton("&Help", action("Help"))
+	input.SetText("Root")
+	menu.SetRoot(&input)
+	screen.SetRoot(&menu)
+	screen.SetHeight(10)
+
+	key := func(k tcell.Key, r rune, mod tcell.ModMask) func() {
+		return func() {
+			screen.Event(tcell.NewEventKey(k, r, mod))
+		}
+	}
+	var buf bytes.Buffer
+	cells := new([][]Cell)
+	for _, ev := range []struct {
+		name string
+		f    func()
+	}{
+		{"none", func() {}},
+		{"FocusRoot", func() { screen.FocusPrev() }},
+		{"F10", key(tcell.KeyF10, ' ', tcell.ModNone)},
+		{"Right", key(tcell.KeyRight, ' ', tcell.ModNone)},
+		{"Down", key(tcell.KeyDown, ' ', tcell.ModNone)},
+		{"Down", key(tcell.KeyDown, ' ', tcell.ModNone)},
+		{"Enter", key(tcell.KeyEnter, ' ', tcell.ModNone)},
+		{"F10", key(tcell.KeyF10, ' ', tcell.ModNone)},
+		{"Left", key(tcell.KeyLeft, ' ', tcell.ModNone)},
+		{"Right", key(tcell.KeyRight, ' ', tcell.ModNone)},
+		{"Down", key(tcell.KeyDown, ' ', tcell.ModNone)},
+		{"Down", key(tcell.KeyDown, ' ', tcell.ModNone)},
+		{"Right", key(tcell.KeyRight, ' ', tcell.ModNone)},
+		{"Left", key(tcell.KeyLeft, ' ', tcell.ModNone)},
+		{"Right", key(tcell.KeyRight, ' ', tcell.ModNone)},
+		{"Escape", key(tcell.KeyEscape, ' ', tcell.ModNone)},
+		{"Escape", key(tcell.KeyEscape, ' ', tcell.ModNone)},
+		{"Escape", key(tcell.KeyEscape, ' ', tcell.ModNone)},
+		{"Alt+F", key(tcell.KeyRune, 'f', tcell.ModAlt)},
+		{"Alt+R", key(tcell.KeyRune, 'r', tcell.ModAlt)},
+		{"Alt+L", key(tcell.KeyRune, 'l', tcell.ModAlt)},
+		{"Alt+H", key(tcell.KeyRune, 'h', tcell.ModAlt)},
+	} {
+		ev.f()
+		screen.GetContents(30, cells)
+		fmt.Fprintf(&buf, "Move: %s\n", ev.name)
+		fmt.Fprintf(&buf, "%s", Convert(*cells))
+	}
+	if fmt.Sprint(actions) != "[Paste Last Help]" {
+		t.Errorf("not valid actions: %v", actions)
+	}
+	if screen.GetFocused() != &input {
+		t.Errorf("focus is not restored")
+	}
+
+	filename := filepath.Join(testdata, "MenuKeyboard")
+	compare.Test(t, filename, buf.Bytes())
+}
+
+func TestTheme(t *testing.T) {
+	unicode := LightTheme()
+	unicode.SpecificSymbol(false)
+	var buf bytes.Buffer
+	for _, tc := range []struct {
+		name  string
+		theme *Theme
+	}{
+		{"Default", nil},
+		{"Light", LightTheme()},
+		{"Unicode", unicode},
+		{"Dark", DarkTheme()},
+		{"HighContrast", HighContrastTheme()},
+	} {
+		var (
+			frame  Frame
+			list   List
+			button Button
+			local  Button
+			th     Themed
+			screen Screen
+		)
+		button.SetText("Button")
+		local.SetText("Local")
+		th.SetRoot(&local)
+		th.SetOverride(func(t *Theme) { t.ButtonStyle = t.ButtonSelectStyle })
+		list.Add(TextStatic("Text"))
+		list.Add(&button)
+		list.Add(&th)
+		frame.SetRoot(&list)
+		screen.SetRoot(&frame)
+		screen.SetTheme(tc.theme)
+		screen.SetHeight(6)
+
+		cells := new([][]Cell)
+		screen.GetContents(20, cells)
+		if (*cells)[3][2].S != screen.GetTheme().ButtonStyle {
+			t.Errorf("%s: button style is not from theme", tc.name)
+		}
+		if (*cells)[4][2].S != screen.GetTheme().ButtonSelectStyle {
+			t.Errorf("%s: local style is not overrided", tc.name)
+		}
+		fmt.Fprintf(&buf, "Theme: %s\n", tc.name)
+		fmt.Fprintf(&buf, "%s", Convert(*cells))
+	}
+
+	filename := filepath.Join(testdata, "Theme")
+	compare.Test(t, filename, buf.Bytes())
+}
+
+func TestThemeSwitch(t *testing.T) {
+	var (
+		frame  Frame
+		list   List
+		button Button
+		screen Screen
+	)
+	button.SetText("Button")
+	list.Add(TextStatic("Text"))
+	list.Add(&button)
+	frame.Header = TextStatic("Header")
+	frame.SetRoot(&list)
+	screen.SetRoot(&frame)
+	screen.SetHeight(5)
+
+	var buf bytes.Buffer
+	cells := new([][]Cell)
+	for _, tc := range []struct {
+		name  string
+		theme *Theme
+	}{
+		{"Light", LightTheme()},
+		{"Dark", DarkTheme()},
+		{"Light", LightTheme()},
+	} {
+		screen.SetTheme(tc.theme)
+		screen.GetContents(20, cells)
+		if (*cells)[0][2].S != tc.theme.TextStyle {
+			t.Errorf("%s: header style is not from theme", tc.name)
+		}
+		if (*cells)[2][2].S != tc.theme.TextStyle {
+			t.Errorf("%s: text style is not from theme", tc.name)
+		}
+		fmt.Fprintf(&buf, "Theme: %s\n", tc.name)
+		fmt.Fprintf(&buf, "%s", ConvertSnapshot(*cells))
+	}
+
+	filename := filepath.Join(testdata, "ThemeSwitch")
+	compare.Test(t, filename, buf.Bytes())
+}
+
+func TestThemedCache(t *testing.T) {
+	var (
+		button Button
+		th     Themed
+		screen Screen
+	)
+	button.SetText("Button")
+	th.SetRoot(&button)
+	screen.SetRoot(&th)
+	screen.SetHeight(2)
+	cells := new([][]Cell)
+	screen.GetContents(20, cells)
+	first := th.inner
+	screen.GetContents(20, cells)
+	if th.inner != first {
+		t.Errorf("theme is rebuilt without changes")
+	}
+	th.SetOverride(func(t *Theme) { t.ButtonStyle = t.ButtonSelectStyle })
+	screen.GetContents(20, cells)
+	if (*cells)[0][2].S != screen.GetTheme().ButtonSelectStyle {
+		t.Errorf("override is not applied")
+	}
+	screen.SetTheme(DarkTheme())
+	screen.GetContents(20, cells)
+	if (*cells)[0][2].S != screen.GetTheme().ButtonSelectStyle {
+		t.Errorf("theme of screen is not applied")
+	}
+}
+
+func TestThemeParallel(t *testing.T) {
+	render := func(th *Theme) tcell.Style {
+		var (
+			button Button
+			screen Screen
+		)
+		button.SetText("Button")
+		screen.SetRoot(&button)
+		screen.SetTheme(th)
+		screen.SetHeight(3)
+		cells := new([][]Cell)
+		screen.GetContents(20, cells)
+		return (*cells)[0][0].S
+	}
+	done := make(chan bool)
+	for _, th := range []*Theme{LightTheme(), DarkTheme()} {
+		go func(th *Theme) {
+			ok := true
+			for i := 0; i < 100; i++ {
+				ok = ok && render(th) == th.ButtonStyle
+			}
+			done <- ok
+		}(th)
+	}
+	for i := 0; i < 2; i++ {
+		if !<-done {
+			t.Errorf("style is not from own theme")
+		}
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	var (
+		frame  Frame
+		list   List
+		button Button
+		input  InputBox
+		screen Screen
+	)
+	button.SetText("Button")
+	input.SetText("Input \"quoted\"\t")
+	list.Add(TextStatic("Text"))
+	list.Add(&button)
+	list.Add(&input)
+	frame.SetRoot(&list)
+	screen.SetRoot(&frame)
+	screen.SetTheme(HighContrastTheme())
+	screen.SetHeight(6)
+
+	cells := new([][]Cell)
+	screen.GetContents(20, cells)
+	(*cells)[1][1].S = (*cells)[1][1].S.Underline(true).Italic(true)
+	(*cells)[1][2].S = (*cells)[1][2].S.Foreground(tcell.NewRGBColor(1, 2, 3))
+	(*cells)[1][3].S = (*cells)[1][3].S.Foreground(tcell.PaletteColor(200))
+	(*cells)[1][4].R = 0
+	snapshot := ConvertSnapshot(*cells)
+
+	parsed, err := ParseSnapshot(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs := DiffCells(parsed, *cells); len(diffs) != 0 {
+		t.Errorf("parsed snapshot is not same:\n%s", strings.Join(diffs, "\n"))
+	}
+	if s := ConvertSnapshot(parsed); s != snapshot {
+		t.Errorf("snapshots are not same")
+	}
+
+	parsed[2][3].S = parsed[2][3].S.Foreground(red)
+	parsed[4][5].R = 'W'
+	parsed = parsed[:5]
+	diffs := DiffCells(parsed, *cells)
+	expect := []string{
+		"rows: actual 5, expect 6",
+		"row 3 col 4: actual 'e' fg:red bg:black, expect 'e' fg:white bg:black",
+		"row 5 col 6: actual 'W' fg:black bg:white, expect 'u' fg:black bg:white",
+	}
+	if strings.Join(diffs, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("not valid diffs:\n%s", strings.Join(diffs, "\n"))
+	}
+
+	for _, s := range []string{
+		"",
+		"vl snapshot 2\nrows 0\n",
+		"vl snapshot 1\n",
+		"vl snapshot 1\nrows 1\n",
+		"vl snapshot 1\nrows 1\nstyle A fg:unknown\n0001 \"a\" A\n",
+		"vl snapshot 1\nrows 1\nstyle A fg:black\n0001 \"a\" B\n",
+		"vl snapshot 1\nrows 1\nstyle A fg:black\n0001 \"ab\" A\n",
+		"vl snapshot 1\nrows 1\nstyle A fg:black\n0002 \"a\" A\n",
+		"vl snapshot 1\nrows 1\nstyle A fg:black\n0001 a A\n",
+	} {
+		if _, err := ParseSnapshot(s); err == nil {
+			t.Errorf("error is not found for snapshot %q", s)
+		}
+	}
+
+	filename := filepath.Join(testdata, "Snapshot")
+	compare.Test(t, filename, []byte(snapshot))
+}
+
+func TestExport(t *testing.T) {
+	var (
+		frame  Frame
+		list   List
+		button Button
+		screen Screen
+	)
+	button.SetText("<Button & \"Export\">")
+	list.Add(TextStatic("Text"))
+	list.Add(&button)
+	frame.SetRoot(&list)
+	screen.SetRoot(&frame)
+	screen.SetHeight(5)
+
+	cells := new([][]Cell)
+	screen.GetContents(24, cells)
+	(*cells)[1][2].S = (*cells)[1][2].S.Bold(true).Underline(true)
+	(*cells)[1][3].S = (*cells)[1][3].S.Reverse(true).Dim(true)
+	(*cells)[1][4].S = (*cells)[1][4].S.Italic(true).StrikeThrough(true)
+	(*cells)[1][5].S = tcell.StyleDefault
+
+	for _, tc := range []struct {
+		name   string
+		export func(io.Writer, [][]Cell) error
+	}{
+		{"Export.html", ExportHTML},
+		{"Export.svg", ExportSVG},
+	} {
+		var buf bytes.Buffer
+		if err := tc.export(&buf, *cells); err != nil {
+			t.Fatal(err)
+		}
+		filename := filepath.Join(testdata, tc.name)
+		compare.Test(t, filename, buf.Bytes())
+	}
+}
+
+type dragRecorder struct {
+	container
+	events []string
+}
+
+func (d *dragRecorder) Render(width uint, dr Drawer) (height uint) {
+	d.StoreSize(width, 2)
+	for col := uint(0); col < width; col++ {
+		dr(0, col, d.theme().ButtonStyle, 'D')
+	}
+	return 2
+}
+
+func (d *dragRecorder) Event(ev tcell.Event) {
+	switch ev := ev.(type) {
+	case *tcell.EventMouse:
+		if button, ok := d.onFocus(ev); ok && button[0] {
+			CaptureMouse(d, ev)
+		}
+	case *EventDrag:
+		col, row := ev.Position()
+		d.events = append(d.events, fmt.Sprintf("%d:%d,%d", ev.State, col, row))
+	}
+}
+
+func TestDrag(t *testing.T) {
+	var (
+		list   List
+		scroll Scroll
+		inner  List
+		button Button
+		rec    dragRecorder
+		screen Screen
+		clicks int
+	)
+	for i := 0; i < 30; i++ {
+		inner.Add(TextStatic(fmt.Sprintf("Line %02d", i)))
+	}
+	scroll.SetRoot(&inner)
+	button.SetText("Button")
+	button.OnClick = func() { clicks++ }
+	list.Add(&button)
+	list.Add(&rec)
+	list.Add(&scroll)
+	screen.SetRoot(&list)
+	screen.SetHeight(24)
+
+	mouse := func(col, row int, button tcell.ButtonMask) func() {
+		return func() {
+			screen.Event(tcell.NewEventMouse(col, row, button, tcell.ModNone))
+		}
+	}
+	var buf bytes.Buffer
+	cells := new([][]Cell)
+	for _, ev := range []struct {
+		name string
+		f    func()
+	}{
+		{"none", func() {}},
+		{"PressThumb", mouse(19, 17, tcell.Button1)},
+		{"DragThumb", mouse(18, 20, tcell.Button1)},
+		{"WheelInDrag", mouse(18, 20, tcell.WheelUp)},
+		{"OverButton", mouse(2, 0, tcell.Button1)},
+		{"Release", mouse(2, 0, tcell.ButtonNone)},
+		{"PressRecorder", mouse(3, 9, tcell.Button1)},
+		{"DragRecorder", mouse(5, 13, tcell.Button1)},
+		{"DragOutside", mouse(-1, 30, tcell.Button1)},
+		{"Release", mouse(0, 0, tcell.ButtonNone)},
+		{"ClickButton", mouse(2, 0, tcell.Button1)},
+		{"Release", mouse(2, 0, tcell.ButtonNone)},
+	} {
+		ev.f()
+		screen.GetContents(20, cells)
+		fmt.Fprintf(&buf, "Move: %s\n", ev.name)
+		fmt.Fprintf(&buf, "%s", Convert(*cells))
+	}
+	if clicks != 1 {
+		t.Errorf("not valid amount of clicks: %d", clicks)
+	}
+	if s := fmt.Sprint(rec.events); s != "[0:3,1 1:5,5 1:-1,22 2:0,-8]" {
+		t.Errorf("not valid drag events: %s", s)
+	}
+
+	filename := filepath.Join(testdata, "Drag")
+	compare.Test(t, filename, buf.Bytes())
+}
+
+func TestDragParallel(t *testing.T) {
+	done := make(chan bool)
+	for g := 0; g < 2; g++ {
+		go func() {
+			var (
+				rec    dragRecorder
+				screen Screen
+			)
+			screen.SetRoot(&rec)
+			screen.SetHeight(4)
+			cells := new([][]Cell)
+			ok := true
+			for i := 0; i < 100; i++ {
+				rec.events = nil
+				screen.GetContents(10, cells)
+				screen.Event(tcell.NewEventMouse(3, 0, tcell.Button1, tcell.ModNone))
+				screen.Event(tcell.NewEventMouse(5, 1, tcell.Button1, tcell.ModNone))
+				screen.Event(tcell.NewEventMouse(5, 1, tcell.ButtonNone, tcell.ModNone))
+				ok = ok && fmt.Sprint(rec.events) == "[0:3,0 1:5,1 2:5,1]"
+			}
+			done <- ok
+		}()
+	}
+	for g := 0; g < 2; g++ {
+		if !<-done {
+			t.Errorf("not valid drag events")
+		}
+	}
+}
+
+func TestSplitPane(t *testing.T) {
+	var (
+		outer  SplitPane
+		inner  SplitPane
+		button Button
+		top    Text
+		bottom Text
+		screen Screen
+	)
+	var changes []uint
+	button.SetText("Button")
+	top.SetText("Top")
+	bottom.SetText("Bottom")
+	inner.SetFirst(&top)
+	inner.SetSecond(&bottom)
+	inner.SecondMin = 2
+	outer.Horizontal = true
+	outer.FirstMin = 6
+	outer.FirstMax = 14
+	outer.SetFirst(&button)
+	outer.SetSecond(&inner)
+	outer.OnChange = func(pos uint) { changes = append(changes, pos) }
+	screen.SetRoot(&outer)
+	screen.SetHeight(7)
+
+	mouse := func(col, row int, button tcell.ButtonMask) func() {
+		return func() {
+			screen.Event(tcell.NewEventMouse(col, row, button, tcell.ModNone))
+		}
+	}
+	key := func(k tcell.Key) func() {
+		return func() {
+			screen.Event(tcell.NewEventKey(k, ' ', tcell.ModNone))
+		}
+	}
+	var buf bytes.Buffer
+	cells := new([][]Cell)
+	for _, ev := range []struct {
+		name string
+		f    func()
+	}{
+		{"none", func() {}},
+		{"PressDivider", mouse(9, 2, tcell.Button1)},
+		{"DragDivider", mouse(12, 4, tcell.Button1)},
+		{"DragOverMax", mouse(17, 4, tcell.Button1)},
+		{"Release", mouse(17, 4, tcell.ButtonNone)},
+		{"Left", key(tcell.KeyLeft)},
+		{"Home", key(tcell.KeyHome)},
+		{"Tab", key(tcell.KeyTab)},
+		{"Down", key(tcell.KeyDown)},
+		{"End", key(tcell.KeyEnd)},
+		{"Up", key(tcell.KeyUp)},
+		{"ClickButton", mouse(1, 0, tcell.Button1)},
+		{"SetPosition", func() {
+			outer.SetPosition(8)
+			inner.SetPosition(0)
+		}},
+	} {
+		ev.f()
+		screen.GetContents(20, cells)
+		fmt.Fprintf(&buf, "Move: %s\n", ev.name)
+		fmt.Fprintf(&buf, "%s", Convert(*cells))
+	}
+	if fmt.Sprint(changes) != "[12 14 13 6]" {
+		t.Errorf("not valid changes: %v", changes)
+	}
+	if pos := outer.GetPosition(); pos != 8 {
+		t.Errorf("not valid position: %d", pos)
+	}
+
+	filename := filepath.Join(testdata, "SplitPane")
+	compare.Test(t, filename, buf.Bytes())
+}
+
+func TestInputBoxSelection(t *testing.T) {
+	var (
+		list      List
+		input     InputBox
+		other     InputBox
+		clipboard MemoryClipboard
+		screen    Screen
+	)
+	input.SetText("Hello, World")
+	input.Clipboard = &clipboard
+	other.SetText("Other")
+	list.Add(&input)
+	list.Add(&other)
+	screen.SetRoot(&list)
+	screen.SetHeight(4)
+
+	mouse := func(col, row int, button tcell.ButtonMask) func() {
+		return func() {
+			screen.Event(tcell.NewEventMouse(col, row, button, tcell.ModNone))
+		}
+	}
+	key := func(k tcell.Key, mod tcell.ModMask) func() {
+		return func() {
+			screen.Event(tcell.NewEventKey(k, 0, mod))
+		}
+	}
+	var buf bytes.Buffer
+	cells := new([][]Cell)
+	for _, ev := range []struct {
+		name string
+		f    func()
+	}{
+		{"none", func() {}},
+		{"Click", mouse(2, 0, tcell.Button1)},
+		{"Release", mouse(2, 0, tcell.ButtonNone)},
+		{"Shift+Right", key(tcell.KeyRight, tcell.ModShift)},
+		{"Shift+Right", key(tcell.KeyRight, tcell.ModShift)},
+		{"Shift+Right", key(tcell.KeyRight, tcell.ModShift)},
+		{"Ctrl+C", key(tcell.KeyCtrlC, tcell.ModCtrl)},
+		{"Right", key(tcell.KeyRight, tcell.ModNone)},
+		{"Ctrl+V", key(tcell.KeyCtrlV, tcell.ModCtrl)},
+		{"Ctrl+A", key(tcell.KeyCtrlA, tcell.ModCtrl)},
+		{"Ctrl+X", key(tcell.KeyCtrlX, tcell.ModCtrl)},
+		{"Shift+Insert", key(tcell.KeyInsert, tcell.ModShift)},
+		{"Press", mouse(0, 0, tcell.Button1)},
+		{"Drag", mouse(5, 0, tcell.Button1)},
+		{"Release", mouse(5, 0, tcell.ButtonNone)},
+		{"InputRune", func() {
+			screen.Event(tcell.NewEventKey(tcell.KeyRune, 'W', tcell.ModNone))
+		}},
+		{"Shift+Left", key(tcell.KeyLeft, tcell.ModShift)},
+		{"Backspace", key(tcell.KeyBackspace2, tcell.ModNone)},
+		{"PressDrag", mouse(1, 0, tcell.Button1)},
+		{"DragOutside", mouse(30, 3, tcell.Button1)},
+		{"Release", mouse(30, 3, tcell.ButtonNone)},
+		{"ClickOther", mouse(1, 2, tcell.Button1)},
+	} {
+		ev.f()
+		screen.GetContents(20, cells)
+		fmt.Fprintf(&buf, "Move: %s\n", ev.name)
+		fmt.Fprintf(&buf, "%s", Convert(*cells))
+	}
+	if s := input.GetText(); s != ",llo World" {
+		t.Errorf("not valid text: %q", s)
+	}
+	if s := input.GetSelectedText(); s != "llo World" {
+		t.Errorf("not valid selected text: %q", s)
+	}
+	if s := clipboard.GetText(); s != "Hello,llo World" {
+		t.Errorf("not valid clipboard: %q", s)
+	}
+	if s := DefaultClipboard.GetText(); s != "" {
+		t.Errorf("default clipboard is used: %q", s)
+	}
+
+	filename := filepath.Join(testdata, "InputBoxSelection")
+	compare.Test(t, filename, buf.Bytes())
+}
+
+func TestInputBoxLayout(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"Hello, World",
+		"one\ntwo\n\nthree",
+		"\n\nwide \u4e16\u754c runes and long line for wrapping\n",
+	} {
+		var input InputBox
+		input.SetText(text)
+		input.Render(8, NilDrawer)
+		size := len([]rune(text))
+		if ps := input.layout(); len(ps) != size+1 {
+			t.Fatalf("%q: not valid amount of positions: %d", text, len(ps))
+		}
+		for i := 0; i <= size; i++ {
+			input.setCursor(i)
+			if c := input.cursor(); c != i {
+				t.Errorf("%q: cursor %d is not same %d", text, c, i)
+			}
+		}
+	}
+}
+
+func TestInputBoxNotRuneKeys(t *testing.T) {
+	var (
+		input  InputBox
+		screen Screen
+	)
+	input.SetText("secret")
+	screen.SetRoot(&input)
+	screen.SetHeight(1)
+	cells := new([][]Cell)
+	screen.GetContents(10, cells)
+	screen.Event(tcell.NewEventMouse(3, 0, tcell.Button1, tcell.ModNone))
+	screen.Event(tcell.NewEventMouse(3, 0, tcell.ButtonNone, tcell.ModNone))
+	screen.Event(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModShift))
+	for _, k := range []tcell.Key{
+		tcell.KeyEscape, tcell.KeyF1, tcell.KeyF12, tcell.KeyInsert, tcell.KeyCtrlB,
+	} {
+		screen.Event(tcell.NewEventKey(k, 0, tcell.ModNone))
+		if s := input.GetText(); s != "secret" {
+			t.Fatalf("text is changed by key %s: %q", tcell.KeyNames[k], s)
+		}
+		if s := input.GetSelectedText(); s != "r" {
+			t.Fatalf("selection is changed by key %s: %q", tcell.KeyNames[k], s)
+		}
+	}
+	input.Undo()
+	if s := input.GetText(); s != "secret" {
+		t.Errorf("not valid text after undo: %q", s)
+	}
+}
+
+func TestOSC52Clipboard(t *testing.T) {
+	var (
+		out bytes.Buffer
+		c   OSC52Clipboard
+	)
+	c.SetText("Empty")
+	if s := c.GetText(); s != "Empty" {
+		t.Errorf("not valid text without writer: %q", s)
+	}
+	c.Writer = &out
+	c.SetText("Hello")
+	if s := out.String(); s != "\x1b]52;c;SGVsbG8=\a" {
+		t.Errorf("not valid escape sequence: %q", s)
+	}
+	if s := c.GetText(); s != "Hello" {
+		t.Errorf("not valid text: %q", s)
+	}
+}
+
+func TestInputBoxUndo(t *testing.T) {
+	var (
+		input  InputBox
+		screen Screen
+	)
+	screen.SetRoot(&input)
+	screen.SetHeight(2)
+	cells := new([][]Cell)
+	screen.GetContents(30, cells)
+	screen.Event(tcell.NewEventMouse(0, 0, tcell.Button1, tcell.ModNone))
+
+	typing := func(text string) {
+		for _, r := range text {
+			screen.Event(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+		}
+	}
+	key := func(k tcell.Key) {
+		screen.Event(tcell.NewEventKey(k, 0, tcell.ModNone))
+	}
+	check := func(expect string) {
+		t.Helper()
+		screen.GetContents(30, cells)
+		if s := input.GetText(); s != expect {
+			t.Errorf("not valid text: %q != %q", s, expect)
+		}
+	}
+
+	typing("hello world")
+	key(tcell.KeyCtrlZ)
+	check("hello")
+	key(tcell.KeyCtrlZ)
+	check("")
+	key(tcell.KeyCtrlZ)
+	check("")
+	key(tcell.KeyCtrlY)
+	check("hello")
+	key(tcell.KeyCtrlY)
+	check("hello world")
+	key(tcell.KeyCtrlY)
+	check("hello world")
+
+	// deletion
+	key(tcell.KeyBackspace2)
+	key(tcell.KeyBackspace2)
+	key(tcell.KeyBackspace2)
+	check("hello wo")
+	key(tcell.KeyLeft)
+	key(tcell.KeyBackspace2)
+	check("hello o")
+	key(tcell.KeyCtrlZ)
+	check("hello wo")
+	key(tcell.KeyCtrlZ)
+	check("hello world")
+	typing("!")
+	check("hello world!")
+	key(tcell.KeyCtrlY)
+	check("hello world!")
+
+	// paste
+	input.Clipboard = new(MemoryClipboard)
+	input.Clipboard.SetText(" and more")
+	key(tcell.KeyCtrlV)
+	check("hello world! and more")
+	key(tcell.KeyCtrlZ)
+	check("hello world!")
+
+	// replace selection
+	key(tcell.KeyCtrlA)
+	typing("new")
+	check("new")
+	key(tcell.KeyCtrlZ)
+	check("n")
+	key(tcell.KeyCtrlZ)
+	check("hello world!")
+
+	// group by time and depth of history
+	defer func(d time.Duration) { HistoryGroupTime = d }(HistoryGroupTime)
+	HistoryGroupTime = 0
+	input.HistoryDepth = 2
+	input.SetText("")
+	typing("abcd")
+	for i := 0; i < 3; i++ {
+		key(tcell.KeyCtrlZ)
+	}
+	check("ab")
+}
+
+func TestPaste(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		filter func(string) (string, bool)
+		expect string
+	}{
+		{"Default", nil, "one\ntwo\tthree"},
+		{"SingleLine", PasteSingleLine, "one two\tthree"},
+		{"RejectNewLines", PasteRejectNewLines, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				list   List
+				input  InputBox
+				other  InputBox
+				screen Screen
+			)
+			input.PasteFilter = tc.filter
+			list.Add(&input)
+			list.Add(&other)
+			screen.SetRoot(&list)
+			screen.SetHeight(10)
+			cells := new([][]Cell)
+			screen.GetContents(30, cells)
+			screen.FocusNext()
+
+			screen.Event(tcell.NewEventPaste(true))
+			for _, r := range "one" {
+				screen.Event(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+			}
+			screen.Event(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+			for _, r := range "two" {
+				screen.Event(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+			}
+			screen.Event(tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone))
+			for _, r := range "three" {
+				screen.Event(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+			}
+			screen.Event(tcell.NewEventPaste(false))
+			screen.GetContents(30, cells)
+
+			if s := input.GetText(); s != tc.expect {
+				t.Errorf("not valid text: %q", s)
+			}
+			if s := other.GetText(); s != "" {
+				t.Errorf("paste in other widget: %q", s)
+			}
+			if screen.GetFocused() != &input {
+				t.Errorf("focus is changed")
+			}
+			screen.Event(tcell.NewEventKey(tcell.KeyCtrlZ, 0, tcell.ModNone))
+			if s := input.GetText(); s != "" {
+				t.Errorf("paste is not single undo step: %q", s)
+			}
+		})
+	}
+}
+
+func TestInputBoxMask(t *testing.T) {
+	var (
+		input  InputBox
+		screen Screen
+	)
+	input.Mask = '*'
+	input.Clipboard = new(MemoryClipboard)
+	screen.SetRoot(&input)
+	screen.SetHeight(1)
+	cells := new([][]Cell)
+	screen.GetContents(20, cells)
+	screen.Event(tcell.NewEventMouse(0, 0, tcell.Button1, tcell.ModNone))
+	for _, r := range "secret" {
+		screen.Event(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	screen.GetContents(20, cells)
+	view := Convert(*cells)
+	if strings.Contains(view, "secret") || !strings.Contains(view, "******") {
+		t.Errorf("text is not masked:\n%s", view)
+	}
+	if s := input.GetText(); s != "secret" {
+		t.Errorf("not valid text: %q", s)
+	}
+	screen.Event(tcell.NewEventKey(tcell.KeyCtrlA, 0, tcell.ModNone))
+	screen.Event(tcell.NewEventKey(tcell.KeyCtrlC, 0, tcell.ModNone))
+	if s := input.Clipboard.GetText(); s != "" {
+		t.Errorf("masked text is copied: %q", s)
+	}
+	if s := input.GetSelectedText(); s != "" {
+		t.Errorf("masked text is selected: %q", s)
+	}
+	screen.Event(tcell.NewEventKey(tcell.KeyCtrlX, 0, tcell.ModNone))
+	if s := input.GetText(); s != "secret" {
+		t.Errorf("masked text is cut: %q", s)
+	}
+	if s := input.Clipboard.GetText(); s != "" {
+		t.Errorf("masked text is copied by cut: %q", s)
+	}
+}
+
+func TestInputBoxPattern(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		keys    []tcell.Key
+		text    string
+		expect  string
+	}{
+		{"99/99/9999", nil, "12a032024", "12/03/2024"},
+		{"99/99/9999", nil, "12/03/2024", "12/03/2024"},
+		{"99/99/9999", nil, "1203202499", "12/03/2024"},
+		{"99/99/9999", nil, "12", "12/"},
+		{"99/99/9999", []tcell.Key{tcell.KeyBackspace2}, "123", "12/"},
+		{"99/99/9999", []tcell.Key{tcell.KeyBackspace2, tcell.KeyBackspace2}, "123", "1"},
+		{"99/99/9999", []tcell.Key{tcell.KeyLeft, tcell.KeyLeft, tcell.KeyLeft, tcell.KeyDelete}, "1234", "12/4"},
+		{"99/99/9999", nil, "1234", "12/34/"},
+		{"+9 (999) 999-9999", nil, "71234567890", "+7 (123) 456-7890"},
+		{"+9 (999) 999-9999", nil, "7", "+7 ("},
+		{"aa-99", nil, "x1y2z3", "xy-23"},
+		{"**", nil, "-a1b", "a1"},
+	} {
+		t.Run(fmt.Sprintf("%s:%s", tc.pattern, tc.text), func(t *testing.T) {
+			var (
+				input  InputBox
+				screen Screen
+			)
+			input.Pattern = tc.pattern
+			screen.SetRoot(&input)
+			screen.SetHeight(1)
+			cells := new([][]Cell)
+			screen.GetContents(30, cells)
+			screen.Event(tcell.NewEventMouse(0, 0, tcell.Button1, tcell.ModNone))
+			for _, r := range tc.text {
+				screen.Event(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+			}
+			for _, k := range tc.keys {
+				screen.Event(tcell.NewEventKey(k, 0, tcell.ModNone))
+			}
+			if s := input.GetText(); s != tc.expect {
+				t.Errorf("not valid text: %q != %q", s, tc.expect)
+			}
+		})
+	}
+}
+
+func TestSpinBox(t *testing.T) {
+	var (
+		list   List
+		spin   SpinBox
+		other  InputBox
+		screen Screen
+		values []float64
+	)
+	spin.Min, spin.Max = -1, 2
+	spin.Step = 0.25
+	spin.Precision = 2
+	spin.OnChange = func(value float64) {
+		values = append(values, value)
+	}
+	list.Add(&spin)
+	list.Add(&other)
+	screen.SetRoot(&list)
+	screen.SetHeight(5)
+	cells := new([][]Cell)
+	screen.GetContents(10, cells)
+	if s := spin.GetText(); s != "0.00" {
+		t.Fatalf("not valid text: %q", s)
+	}
+	check := func(expect float64) {
+		t.Helper()
+		screen.GetContents(10, cells)
+		if v := spin.GetValue(); v != expect {
+			t.Errorf("not valid value: %v != %v", v, expect)
+		}
+		if s, e := spin.GetText(), spin.format(expect); s != e {
+			t.Errorf("not valid text: %q != %q", s, e)
+		}
+	}
+	key := func(k tcell.Key) {
+		screen.Event(tcell.NewEventKey(k, 0, tcell.ModNone))
+	}
+	// click after arrows
+	spin.Event(tcell.NewEventMouse(10, 0, tcell.Button1, tcell.ModNone))
+	check(0)
+	// arrows
+	screen.Event(tcell.NewEventMouse(9, 0, tcell.Button1, tcell.ModNone))
+	check(0.25)
+	screen.Event(tcell.NewEventMouse(8, 0, tcell.Button1, tcell.ModNone))
+	check(0)
+	// keys
+	key(tcell.KeyUp)
+	key(tcell.KeyUp)
+	check(0.5)
+	key(tcell.KeyDown)
+	check(0.25)
+	// mouse wheel
+	screen.Event(tcell.NewEventMouse(2, 0, tcell.WheelUp, tcell.ModNone))
+	check(0.5)
+	screen.Event(tcell.NewEventMouse(2, 0, tcell.WheelDown, tcell.ModNone))
+	check(0.25)
+	// range
+	for i := 0; i < 20; i++ {
+		key(tcell.KeyUp)
+	}
+	check(2)
+	// typing with validation by Enter
+	spin.SelectAll()
+	for _, r := range "-1.5x" {
+		screen.Event(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	key(tcell.KeyEnter)
+	check(-1)
+	// not valid text with validation by focus lost
+	spin.SelectAll()
+	for _, r := range "--" {
+		screen.Event(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	screen.Event(tcell.NewEventMouse(2, 2, tcell.Button1, tcell.ModNone))
+	check(-1)
+	spin.SelectAll()
+	screen.FocusNext()
+	if screen.GetFocused() != &spin {
+		t.Fatalf("spin box is not focused")
+	}
+	screen.Event(tcell.NewEventKey(tcell.KeyRune, '1', tcell.ModNone))
+	screen.FocusNext()
+	check(1)
+	expect := []float64{0.25, 0, 0.25, 0.5, 0.25, 0.5, 0.25, 0.5, 0.75, 1, 1.25, 1.5, 1.75, 2, -1, 1}
+	if fmt.Sprint(values) != fmt.Sprint(expect) {
+		t.Errorf("not valid values of OnChange:\n%v\n%v", values, expect)
+	}
+}
+
+func TestSpinBoxFilter(t *testing.T) {
+	var (
+		spin   SpinBox
+		screen Screen
+	)
+	spin.SetValue(5)
+	spin.Filter(func(r rune) bool { return r != '9' })
+	screen.SetRoot(&spin)
+	screen.SetHeight(1)
+	cells := new([][]Cell)
+	screen.GetContents(10, cells)
+	screen.FocusNext()
+	spin.SelectAll()
+	for _, r := range "19x" {
+		screen.Event(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	screen.GetContents(10, cells)
+	if s := spin.GetText(); s != "1x" {
+		t.Errorf("filter of user is not used: %q", s)
+	}
+}
+
+func TestViewerSearch(t *testing.T) {
+	var (
+		v      Viewer
+		screen Screen
+		buf    bytes.Buffer
+	)
+	v.SetText("First line with Go\n\nsecond line\nthird line with go and GO\nlast line")
+	v.SetColorize(TypicalColorize([]string{"line"}, Style(tcell.ColorWhite, tcell.ColorGreen)))
+	screen.SetRoot(&v)
+	screen.SetHeight(4)
+	cells := new([][]Cell)
+	view := func(name string) {
+		screen.GetContents(15, cells)
+		current, total := v.SearchStatus()
+		fmt.Fprintf(&buf, "Move: %s, position %d, match %d/%d\n%s",
+			name, v.GetPosition(), current, total, Convert(*cells))
+	}
+	key := func(k tcell.Key, r rune) {
+		screen.Event(tcell.NewEventKey(k, r, tcell.ModNone))
+	}
+	view("none")
+	v.SearchIgnoreCase = true
+	screen.Event(tcell.NewEventMouse(0, 0, tcell.Button1, tcell.ModNone))
+	key(tcell.KeyRune, '/')
+	view("open")
+	key(tcell.KeyRune, 'g')
+	view("g")
+	key(tcell.KeyRune, 'o')
+	view("go")
+	key(tcell.KeyEnter, 0)
+	view("Enter")
+	key(tcell.KeyRune, 'n')
+	view("next")
+	key(tcell.KeyRune, 'N')
+	view("prev")
+	key(tcell.KeyRune, 'N')
+	view("prev")
+	key(tcell.KeyEscape, 0)
+	view("Escape")
+	key(tcell.KeyCtrlF, 0)
+	key(tcell.KeyRune, 'x')
+	view("not found")
+	key(tcell.KeyBackspace2, 0)
+	key(tcell.KeyRune, 'l')
+	key(tcell.KeyEscape, 0)
+	view("cancel")
+	compare.Test(t, filepath.Join(testdata, "ViewerSearch"), buf.Bytes())
+
+	for _, tc := range []struct {
+		query      string
+		ignoreCase bool
+		regexp     bool
+		matches    int
+		isErr      bool
+	}{
+		{"go", false, false, 1, false},
+		{"go", true, false, 3, false},
+		{"g.", false, false, 0, false},
+		{"g.", false, true, 1, false},
+		{"g.", true, true, 3, false},
+		{"l[a-z]+e", true, true, 4, false},
+		{"(", false, false, 0, false},
+		{"(", false, true, 0, true},
+	} {
+		v.SearchIgnoreCase = tc.ignoreCase
+		v.SearchRegexp = tc.regexp
+		matches, err := v.Search(tc.query)
+		if matches != tc.matches || (err != nil) != tc.isErr {
+			t.Errorf("%q ignore case %v regexp %v: %d matches, error %v",
+				tc.query, tc.ignoreCase, tc.regexp, matches, err)
+		}
+	}
+}
+
+func TestViewerPreformatted(t *testing.T) {
+	text := "func main() {\n\tif true {\n\t\tfmt.Println(\"long line of text\")\n\t}\r\n\n}"
+	var buf bytes.Buffer
+	for _, noWrap := range []bool{false, true} {
+		var (
+			v      Viewer
+			screen Screen
+		)
+		v.SetText(text)
+		v.Preformatted = true
+		v.NoWrap = noWrap
+		v.TabWidth = 4
+		screen.SetRoot(&v)
+		screen.SetHeight(8)
+		cells := new([][]Cell)
+		view := func(name string) {
+			screen.GetContents(20, cells)
+			fmt.Fprintf(&buf, "Move: %s, NoWrap %v, position %d, column %d\n%s",
+				name, noWrap, v.GetPosition(), v.GetColumn(), Convert(*cells))
+		}
+		view("none")
+		for row := range v.linePos {
+			for col := range v.linePos[row] {
+				fmt.Fprintf(&buf, "%02d ", v.linePos[row][col])
+			}
+			fmt.Fprintf(&buf, "\n")
+		}
+		// position of each line
+		var pos uint
+		for _, line := range v.lines() {
+			v.SetPosition(pos)
+			screen.GetContents(20, cells)
+			var top string
+			for _, c := range (*cells)[0] {
+				top += string(c.R)
+			}
+			if prefix := []rune(line); len(prefix) != 0 &&
+				!strings.HasPrefix(top, string(prefix[:1])) {
+				t.Errorf("NoWrap %v: position %d: %q is not line %q", noWrap, pos, top, line)
+			}
+			pos += uint(len([]rune(line))) + 1
+		}
+		v.SetPosition(0)
+		screen.Event(tcell.NewEventMouse(0, 0, tcell.Button1, tcell.ModNone))
+		for i := 0; i < 3; i++ {
+			screen.Event(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone))
+		}
+		view("Right")
+		screen.Event(tcell.NewEventKey(tcell.KeyHome, 0, tcell.ModNone))
+		view("Home")
+		if _, err := v.Search("text"); err != nil {
+			t.Fatal(err)
+		}
+		view("Search")
+	}
+	compare.Test(t, filepath.Join(testdata, "ViewerPreformatted"), buf.Bytes())
+}
+
+func TestWordWrap(t *testing.T) {
+	text := "Word-aware wrapping breaks lines at spaces and hyphens, " +
+		"so prose is easy to read. Extraordinarily long words are split."
+	var buf bytes.Buffer
+	for _, justify := range []bool{false, true} {
+		for _, width := range []uint{12, 25} {
+			var (
+				v      Viewer
+				list   List
+				txt    Text
+				screen Screen
+			)
+			v.SetText(text)
+			v.WordWrap = true
+			v.Justify = justify
+			screen.SetRoot(&v)
+			screen.SetHeight(12)
+			cells := new([][]Cell)
+			screen.GetContents(width, cells)
+			fmt.Fprintf(&buf, "Viewer: justify %v\n%s", justify, Convert(*cells))
+			// all runes of text are shown
+			var shown string
+			for _, row := range *cells {
+				for _, c := range row {
+					shown += string(c.R)
+				}
+			}
+			shown = strings.Join(strings.Fields(shown), "")
+			if expect := strings.Join(strings.Fields(text), ""); shown != expect {
+				t.Errorf("not valid text:\n%s\n%s", shown, expect)
+			}
+
+			txt.SetText("Text with\nnew line and Extraordinarily long words")
+			txt.SetWordWrap(true, justify)
+			list.Add(&txt)
+			list.Add(TextStaticWrap("Static text label wrapped by words", justify))
+			screen.SetRoot(&list)
+			screen.SetHeight(12)
+			screen.GetContents(width, cells)
+			fmt.Fprintf(&buf, "Text: justify %v\n%s", justify, Convert(*cells))
+		}
+	}
+	compare.Test(t, filepath.Join(testdata, "WordWrap"), buf.Bytes())
+}
+
+func TestMarkdown(t *testing.T) {
+	text := "# Markdown *viewer*\n\n" +
+		"Text with **bold**, *italic*, `code` and snake_case words\n" +
+		"continued on next line with [first link](http://first).\n\n" +
+		"* bullet item with long text for wrapping\n" +
+		"* item with [second](http://second)\n" +
+		"  - nested item\n" +
+		"1. numbered item\n" +
+		"2. numbered item\n\n" +
+		"> quote of text\n> on two lines\n\n" +
+		"---\n\n" +
+		"```\nfunc main() {\n\tfmt.Println(\"long line of code\")\n}\n```\n" +
+		"Last \\*escaped\\* paragraph."
+	var (
+		md     Markdown
+		url    string
+		buf    bytes.Buffer
+		screen Screen
+	)
+	md.SetText(text)
+	md.OnLink = func(u string) { url = u }
+	screen.SetRoot(&md)
+	screen.SetHeight(12)
+	cells := new([][]Cell)
+	view := func(name string) {
+		screen.GetContents(30, cells)
+		fmt.Fprintf(&buf, "Move: %s, position %d, link %q\n%s",
+			name, md.GetPosition(), url, Convert(*cells))
+	}
+	view("none")
+	screen.Event(tcell.NewEventMouse(29, 0, tcell.Button1, tcell.ModNone))
+	// click on link
+	for row := range *cells {
+		for col := range (*cells)[row] {
+			if (*cells)[row][col].S == LightTheme().LinkStyle {
+				screen.Event(tcell.NewEventMouse(col, row, tcell.Button1, tcell.ModNone))
+				break
+			}
+		}
+		if url != "" {
+			break
+		}
+	}
+	if url != "http://first" {
+		t.Errorf("not valid link: %q", url)
+	}
+	view("Click")
+	screen.Event(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone))
+	screen.Event(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if url != "http://second" {
+		t.Errorf("not valid link: %q", url)
+	}
+	view("Right")
+	screen.Event(tcell.NewEventKey(tcell.KeyPgDn, 0, tcell.ModNone))
+	view("PgDn")
+	screen.Event(tcell.NewEventKey(tcell.KeyPgUp, 0, tcell.ModNone))
+	view("PgUp")
+	screen.Event(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	view("Down")
+	if n, err := md.Search("item"); err != nil || n != 5 {
+		t.Errorf("not valid search: %d %v", n, err)
+	}
+	view("Search")
+	compare.Test(t, filepath.Join(testdata, "Markdown"), buf.Bytes())
+}
+
+func TestMarkdownLink(t *testing.T) {
+	for _, tc := range []struct {
+		text, runes string
+		links       []string
+	}{
+		{"[x] and [y](z)", "[x] and y", []string{"z"}},
+		{"[a [b] c](u)", "a [b] c", []string{"u"}},
+		{"[x] (y)", "[x] (y)", nil},
+		{"[x](y", "[x](y", nil},
+	} {
+		var links []string
+		rs, _, _ := parseInline(tc.text, &links)
+		if string(rs) != tc.runes {
+			t.Errorf("%q: not valid text: %q", tc.text, string(rs))
+		}
+		if fmt.Sprint(links) != fmt.Sprint(tc.links) {
+			t.Errorf("%q: not valid links: %q", tc.text, links)
+		}
+	}
+}
+
+func TestMarkdownEmpty(t *testing.T) {
+	for _, text := range []string{"", "> ", "```\n```", "```\n```\n"} {
+		var (
+			md     Markdown
+			screen Screen
+		)
+		md.SetText(text)
+		screen.SetRoot(&md)
+		screen.SetHeight(3)
+		cells := new([][]Cell)
+		screen.GetContents(20, cells)
+		if _, height := md.GetSize(); height != 1 {
+			t.Errorf("%q: not valid height: %d", text, height)
+		}
+	}
+}
+
+func TestViewerEmpty(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		mode func(v *Viewer)
+	}{
+		{"Plain", func(v *Viewer) {}},
+		{"WordWrap", func(v *Viewer) { v.WordWrap = true }},
+		{"Justify", func(v *Viewer) { v.WordWrap, v.Justify = true, true }},
+		{"Preformatted", func(v *Viewer) { v.Preformatted = true }},
+	} {
+		for _, text := range []string{"", " ", "  \n "} {
+			var (
+				v      Viewer
+				screen Screen
+			)
+			v.SetText(text)
+			tc.mode(&v)
+			screen.SetRoot(&v)
+			screen.SetHeight(3)
+			cells := new([][]Cell)
+			screen.GetContents(20, cells)
+			if _, height := v.GetSize(); height == 0 {
+				t.Errorf("%s %q: not valid height: %d", tc.name, text, height)
+			}
+			v.NextPage()
+			v.PrevPage()
+			screen.GetContents(20, cells)
+		}
+	}
+}
+
+func TestViewerANSI(t *testing.T) {
+	text := "\x1b[1;31mFAIL\x1b[0m: TestA \x1b[4munderline\x1b[24m done\n" +
+		"\x1b[38;5;208mpalette\x1b[39m \x1b[48;2;10;20;30mtruecolor\x1b[m\n" +
+		"\x1b[7mreverse\x1b[27m \x1b[92mbright\x1b[0m \x1b[2Kclear\x1b]0;title\x07ok"
+	var buf bytes.Buffer
+	for _, preformatted := range []bool{false, true} {
+		var (
+			v      Viewer
+			screen Screen
+		)
+		v.SetText(text)
+		v.Preformatted = preformatted
+		v.SetColorize(TypicalColorize([]string{"done"}, tcell.StyleDefault.Background(tcell.ColorYellow)))
+		screen.SetRoot(&v)
+		screen.SetHeight(8)
+		cells := new([][]Cell)
+		screen.GetContents(40, cells)
+		fmt.Fprintf(&buf, "Preformatted %v\n%s", preformatted, Convert(*cells))
+		if strings.ContainsRune(fmt.Sprint(*cells), '\x1b') {
+			t.Errorf("escape sequence is not removed")
+		}
+		find := func(text string) (st tcell.Style) {
+			for row := range *cells {
+				var line string
+				for _, c := range (*cells)[row] {
+					line += string(c.R)
+				}
+				if index := strings.Index(line, text); 0 <= index {
+					return (*cells)[row][len([]rune(line[:index]))].S
+				}
+			}
+			t.Fatalf("cannot find %q", text)
+			return
+		}
+		for _, tc := range []struct {
+			text  string
+			style tcell.Style
+		}{
+			{"FAIL", LightTheme().TextStyle.Foreground(tcell.ColorMaroon).Bold(true)},
+			{"TestA", LightTheme().TextStyle},
+			{"underline", LightTheme().TextStyle.Underline(true)},
+			{"done", tcell.StyleDefault.Background(tcell.ColorYellow)},
+			{"palette", LightTheme().TextStyle.Foreground(tcell.PaletteColor(208))},
+			{"truecolor", LightTheme().TextStyle.Background(tcell.NewRGBColor(10, 20, 30))},
+			{"reverse", LightTheme().TextStyle.Reverse(true)},
+			{"bright", LightTheme().TextStyle.Foreground(tcell.ColorLime)},
+			{"clear", LightTheme().TextStyle},
+			{"ok", LightTheme().TextStyle},
+		} {
+			if st := find(tc.text); st != tc.style {
+				t.Errorf("Preformatted %v: not valid style of %q", preformatted, tc.text)
+			}
+		}
+	}
+	compare.Test(t, filepath.Join(testdata, "ViewerANSI"), buf.Bytes())
+}
+
+func TestParseANSIMalformed(t *testing.T) {
+	for _, tc := range []struct {
+		str, text string
+	}{
+		{"\x1b[31\n\u00e9 tail\nnext", "\n\u00e9 tail\nnext"},
+		{"\x1b[31\u00e9 tail", "\u00e9 tail"},
+		{"text\x1b[", "text"},
+		{"text\x1b[31", "text"},
+		{"text\x1b", "text"},
+		{"\x1b[1;\x1b[31mred", "red"},
+		{"\x1b[2Kclear\x1b[0m", "clear"},
+	} {
+		text, styles := parseANSI(tc.str)
+		if text != tc.text {
+			t.Errorf("%q: not valid text: %q", tc.str, text)
+		}
+		if len(styles) != len([]rune(text)) {
+			t.Errorf("%q: not valid amount of styles: %d", tc.str, len(styles))
+		}
+	}
+	// style of not valid sequence is not applied
+	_, styles := parseANSI("\x1b[31\ntext")
+	if styles[1] != (ansiStyle{}) {
+		t.Errorf("style of not valid sequence is applied")
+	}
+}
+
+func TestRegexpColorize(t *testing.T) {
+	var (
+		red    = tcell.StyleDefault.Foreground(tcell.ColorRed)
+		green  = tcell.StyleDefault.Foreground(tcell.ColorGreen)
+		blue   = tcell.StyleDefault.Foreground(tcell.ColorBlue)
+		yellow = tcell.StyleDefault.Background(tcell.ColorYellow)
+	)
+	text := "2024-01-02 10:20:30 ERROR: connect to 192.168.0.1 failed \"Time-out\"\n" +
+		"2024-01-02 10:20:31 INFO: retry"
+	var (
+		v      Viewer
+		screen Screen
+	)
+	v.SetText(text)
+	v.Preformatted = true
+	v.SetColorize(
+		TypicalColorize([]string{"failed", "info"}, yellow),
+		RegexpColorize(
+			RegexpStyle{Regexp: regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}`), Style: green},
+			RegexpStyle{Regexp: regexp.MustCompile(`(ERROR|INFO):`), Groups: map[int]tcell.Style{1: red}},
+			RegexpStyle{Regexp: regexp.MustCompile(`\d+\.\d+\.\d+\.\d+`), Style: blue},
+			RegexpStyle{Regexp: regexp.MustCompile(`"([^"]*)"`), Style: blue,
+				Groups: map[int]tcell.Style{1: green}},
+			// priority of next regexp
+			RegexpStyle{Regexp: regexp.MustCompile(`INFO`), Style: blue},
+		),
+	)
+	screen.SetRoot(&v)
+	screen.SetHeight(4)
+	cells := new([][]Cell)
+	screen.GetContents(70, cells)
+	for _, tc := range []struct {
+		row, col int
+		style    tcell.Style
+	}{
+		{0, 0, green},  // date
+		{0, 18, green}, // time
+		{0, 19, LightTheme().TextStyle},
+		{0, 20, red},                    // ERROR
+		{0, 25, LightTheme().TextStyle}, // colon outside group
+		{0, 38, blue},                   // ip address
+		{0, 48, blue},                   // ip address
+		{0, 50, yellow},                 // failed
+		{0, 57, LightTheme().TextStyle}, // quote outside group
+		{0, 58, green},                  // Time
+		{0, 62, green},                  // hyphen is not changed by TypicalColorize
+		{1, 20, blue},                   // INFO
+	} {
+		if st := (*cells)[tc.row][tc.col].S; st != tc.style {
+			t.Errorf("not valid style of cell %d,%d: %q", tc.row, tc.col, (*cells)[tc.row][tc.col].R)
+		}
+	}
+	compare.Test(t, filepath.Join(testdata, "RegexpColorize"), []byte(Convert(*cells)))
+}
+
+func TestRegexpColorizePartOfWord(t *testing.T) {
+	var (
+		red  = tcell.StyleDefault.Foreground(tcell.ColorRed)
+		blue = tcell.StyleDefault.Foreground(tcell.ColorBlue)
+		text = LightTheme().TextStyle
+	)
+	var (
+		v      Viewer
+		screen Screen
+	)
+	v.SetText("foobar ERROR")
+	v.SetColorize(RegexpColorize(
+		RegexpStyle{Regexp: regexp.MustCompile(`(foo)bar`), Groups: map[int]tcell.Style{1: red}},
+		RegexpStyle{Regexp: regexp.MustCompile(`ERR`), Style: blue},
+	))
+	screen.SetRoot(&v)
+	screen.SetHeight(1)
+	cells := new([][]Cell)
+	screen.GetContents(20, cells)
+	for col, st := range []tcell.Style{
+		red, red, red, text, text, text, // foobar
+		text,                         // space
+		blue, blue, blue, text, text, // ERROR
+	} {
+		if (*cells)[0][col].S != st {
+			t.Errorf("not valid style of cell %d: %q", col, (*cells)[0][col].R)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// Package vltest for headless testing of tui applications based on vl
+package vltest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Konstantin8105/compare"
+	"github.com/Konstantin8105/vl"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Harness is screen with widget mounted for testing without terminal.
+// Golden files of Compare and CompareCells are updated by command:
+//
+//	UPDATE=true go test
+type Harness struct {
+	// Screen with mounted widget
+	Screen vl.Screen
+	// Action is queue of actions run by Advance
+	Action chan func()
+
+	width, height uint
+	cells         [][]vl.Cell
+	buf           bytes.Buffer
+}
+
+// ActionSize is size of action queue
+var ActionSize = 100
+
+// Mount return harness with widget root inside screen with size
+// width and height
+func Mount(root vl.Widget, width, height uint) *Harness {
+	h := &Harness{Action: make(chan func(), ActionSize)}
+	h.Screen.SetRoot(root)
+	h.Resize(width, height)
+	return h
+}
+
+// Resize change size of screen
+func (h *Harness) Resize(width, height uint) {
+	h.width = width
+	h.height = height
+}
+
+// Render draw screen and return cells of screen
+func (h *Harness) Render() [][]vl.Cell {
+	h.Screen.SetHeight(h.height)
+	h.Screen.GetContents(h.width, &h.cells)
+	return h.cells
+}
+
+// String return text view of screen
+func (h *Harness) String() string {
+	return vl.Convert(h.Render())
+}
+
+// Event send event to screen
+func (h *Harness) Event(ev tcell.Event) {
+	h.Screen.Event(ev)
+}
+
+// Key send key event by name. Name is single rune or name of key
+// from tcell.KeyNames with modifiers like in tcell.EventKey.Name,
+// for example: "a", "Enter", "Ctrl-C", "Alt+Rune[f]", "Shift+Left".
+func (h *Harness) Key(name string) error {
+	ev, err := ParseKey(name)
+	if err != nil {
+		return err
+	}
+	h.Event(ev)
+	return nil
+}
+
+// Type send key events for each rune of text
+func (h *Harness) Type(text string) {
+	for _, r := range text {
+		h.Event(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+}
+
+// Paste send text as bracketed paste
+func (h *Harness) Paste(text string) {
+	h.Event(tcell.NewEventPaste(true))
+	for _, r := range text {
+		switch r {
+		case '\n':
+			h.Event(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+		case '\t':
+			h.Event(tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone))
+		default:
+			h.Event(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+		}
+	}
+	h.Event(tcell.NewEventPaste(false))
+}
+
+// Mouse send mouse event with button at column col and row row
+func (h *Harness) Mouse(col, row uint, button tcell.ButtonMask) {
+	h.Event(tcell.NewEventMouse(int(col), int(row), button, tcell.ModNone))
+}
+
+// Click send left button press and release at column col and row row
+func (h *Harness) Click(col, row uint) {
+	h.Mouse(col, row, tcell.Button1)
+	h.Mouse(col, row, tcell.ButtonNone)
+}
+
+// Drag send left button press at column fromCol and row fromRow,
+// mouse motion to column toCol and row toRow and button release
+func (h *Harness) Drag(fromCol, fromRow, toCol, toRow uint) {
+	h.Mouse(fromCol, fromRow, tcell.Button1)
+	h.Mouse(toCol, toRow, tcell.Button1)
+	h.Mouse(toCol, toRow, tcell.ButtonNone)
+}
+
+// Find return position of first cell with text on rendered screen
+func (h *Harness) Find(text string) (col, row uint, found bool) {
+	if text == "" {
+		return
+	}
+	cells := h.Render()
+	for r := range cells {
+		var line strings.Builder
+		for c := range cells[r] {
+			line.WriteRune(cells[r][c].R)
+		}
+		if index := strings.Index(line.String(), text); 0 <= index {
+			col = uint(len([]rune(line.String()[:index])))
+			row = uint(r)
+			found = true
+			return
+		}
+	}
+	return
+}
+
+// ClickText send left button click at first cell with text
+func (h *Harness) ClickText(text string) error {
+	col, row, found := h.Find(text)
+	if !found {
+		return fmt.Errorf("cannot find text %q on screen", text)
+	}
+	h.Click(col, row)
+	return nil
+}
+
+// QueueUpdate add function in queue of actions
+func (h *Harness) QueueUpdate(f func()) {
+	h.Action <- f
+}
+
+// Advance run all actions in queue, include actions added by other
+// actions, and return amount of run actions
+func (h *Harness) Advance() (n int) {
+	for {
+		select {
+		case f := <-h.Action:
+			if f != nil {
+				f()
+			}
+			n++
+		default:
+			return
+		}
+	}
+}
+
+// Snapshot add rendered screen with name to golden result
+func (h *Harness) Snapshot(name string) {
+	fmt.Fprintf(&h.buf, "Move: %s\n", name)
+	fmt.Fprintf(&h.buf, "%s", h.String())
+}
+
+// Compare golden result with golden file
+func (h *Harness) Compare(t testing.TB, filename string) {
+	t.Helper()
+	compare.Test(t, filename, h.buf.Bytes())
+}
+
+// MaxDiffs is maximal amount of printed cell differences
+var MaxDiffs = 20
+
+// CompareCells compare rendered screen with snapshot file created by
+// vl.ConvertSnapshot and print differences of cells
+func (h *Harness) CompareCells(t testing.TB, filename string) {
+	t.Helper()
+	cells := h.Render()
+	if os.Getenv(compare.Key) == compare.KeyValid {
+		snapshot := vl.ConvertSnapshot(cells)
+		if err := os.WriteFile(filename, []byte(snapshot), 0644); err != nil {
+			t.Errorf("cannot write snapshot: %v", err)
+			return
+		}
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Errorf("cannot read snapshot: %v", err)
+		return
+	}
+	expect, err := vl.ParseSnapshot(string(content))
+	if err != nil {
+		t.Errorf("%s: %v", filename, err)
+		return
+	}
+	diffs := vl.DiffCells(cells, expect)
+	if len(diffs) == 0 {
+		return
+	}
+	if MaxDiffs < len(diffs) {
+		diffs = append(diffs[:MaxDiffs], fmt.Sprintf("and %d differences more", len(diffs)-MaxDiffs))
+	}
+	t.Errorf("%s: screen is not same:\n%s", filename, strings.Join(diffs, "\n"))
+}
+
+// ParseKey return key event by name. See Harness.Key.
+func ParseKey(name string) (*tcell.EventKey, error) {
+	var mod tcell.ModMask
+	rest := name
+	for {
+		found := false
+		for _, m := range []struct {
+			prefix string
+			mod    tcell.ModMask
+		}{
+			{"Shift+", tcell.ModShift},
+			{"Alt+", tcell.ModAlt},
+			{"Meta+", tcell.ModMeta},
+			{"Ctrl+", tcell.ModCtrl},
+		} {
+			if strings.HasPrefix(rest, m.prefix) {
+				rest = rest[len(m.prefix):]
+				mod |= m.mod
+				found = true
+			}
+		}
+		if !found || rest == "" {
+			break
+		}
+	}
+	if rest == "" {
+		return nil, fmt.Errorf("key name %q is empty", name)
+	}
+	for key, n := range tcell.KeyNames {
+		if n == rest || (mod&tcell.ModCtrl != 0 && n == "Ctrl-"+rest) {
+			return tcell.NewEventKey(key, 0, mod), nil
+		}
+	}
+	if rs := []rune(rest); len(rs) == 1 {
+		return tcell.NewEventKey(tcell.KeyRune, rs[0], mod), nil
+	}
+	if strings.HasPrefix(rest, "Rune[") && strings.HasSuffix(rest, "]") {
+		if rs := []rune(rest[5 : len(rest)-1]); len(rs) == 1 {
+			return tcell.NewEventKey(tcell.KeyRune, rs[0], mod), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key name %q", name)
+}
@@ -0,0 +1,168 @@
+package vltest
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/Konstantin8105/vl"
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestHarness(t *testing.T) {
+	var (
+		list   vl.List
+		text   vl.Text
+		input  vl.InputBox
+		button vl.Button
+	)
+	text.SetText("Text")
+	input.SetText("Input")
+	button.SetText("Apply")
+	list.Add(&text)
+	list.Add(&input)
+	list.Add(&button)
+
+	h := Mount(&list, 20, 6)
+	button.OnClick = func() {
+		h.QueueUpdate(func() { text.SetText("Applied: " + input.GetText()) })
+	}
+	h.Snapshot("none")
+	if err := h.ClickText("Input"); err != nil {
+		t.Fatal(err)
+	}
+	h.Paste("e")
+	h.Type("d")
+	h.Snapshot("Type")
+	h.Drag(0, 2, 2, 2)
+	h.Snapshot("Drag")
+	if err := h.Key("Right"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.ClickText("Apply"); err != nil {
+		t.Fatal(err)
+	}
+	h.Snapshot("ClickApply")
+	if n := h.Advance(); n != 1 {
+		t.Errorf("not valid amount of actions: %d", n)
+	}
+	h.Snapshot("Advance")
+	if err := h.ClickText("Cancel"); err == nil {
+		t.Errorf("click on not exist text")
+	}
+	h.Resize(10, 4)
+	h.Snapshot("Resize")
+	h.Compare(t, filepath.Join("testdata", "Harness"))
+	h.CompareCells(t, filepath.Join("testdata", "HarnessCells"))
+}
+
+func TestClick(t *testing.T) {
+	var (
+		list   vl.List
+		input  vl.InputBox
+		button vl.Button
+		clicks int
+	)
+	input.SetText("Input")
+	button.SetText("Apply")
+	button.OnClick = func() { clicks++ }
+	list.Add(&input)
+	list.Add(&button)
+
+	h := Mount(&list, 20, 4)
+	for _, text := range []string{"Input", "Apply", "Input", "Apply"} {
+		if err := h.ClickText(text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if clicks != 2 {
+		t.Errorf("not valid amount of clicks: %d", clicks)
+	}
+	if s := input.GetSelectedText(); s != "" {
+		t.Errorf("text is selected: %q", s)
+	}
+	if s := input.GetText(); s != "Input" {
+		t.Errorf("not valid text: %q", s)
+	}
+}
+
+// dragWidget is widget outside of package vl with mouse capture
+type dragWidget struct {
+	screen        *vl.Screen
+	width, height uint
+	events        []string
+}
+
+func (d *dragWidget) Render(width uint, dr vl.Drawer) (height uint) {
+	d.width, d.height = width, 2
+	return d.height
+}
+
+func (d *dragWidget) Event(ev tcell.Event) {
+	switch ev := ev.(type) {
+	case *tcell.EventMouse:
+		if ev.Buttons() == tcell.Button1 {
+			d.screen.CaptureMouse(d, ev)
+		}
+		d.events = append(d.events, "mouse")
+	case *vl.EventDrag:
+		col, row := ev.Position()
+		d.events = append(d.events, fmt.Sprintf("drag%d:%d,%d", ev.State, col, row))
+	}
+}
+
+func (d *dragWidget) Focus(focus bool)              {}
+func (d *dragWidget) StoreSize(width, height uint)  { d.width, d.height = width, height }
+func (d *dragWidget) GetSize() (width, height uint) { return d.width, d.height }
+
+func TestCaptureMouse(t *testing.T) {
+	var (
+		list vl.List
+		text vl.Text
+		drag dragWidget
+	)
+	text.SetText("Text")
+	list.Add(&text)
+	list.Add(&drag)
+	h := Mount(&list, 10, 4)
+	drag.screen = &h.Screen
+	h.Render()
+	h.Drag(3, 2, 5, 3)
+	if got, expect := fmt.Sprint(drag.events), "[mouse drag0:3,0 drag1:5,1 drag2:5,1]"; got != expect {
+		t.Errorf("not valid events:\n%s\n%s", got, expect)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		key  tcell.Key
+		ch   rune
+		mod  tcell.ModMask
+	}{
+		{"a", tcell.KeyRune, 'a', tcell.ModNone},
+		{"+", tcell.KeyRune, '+', tcell.ModNone},
+		{"Alt++", tcell.KeyRune, '+', tcell.ModAlt},
+		{"Enter", tcell.KeyEnter, 0, tcell.ModNone},
+		{"Ctrl-C", tcell.KeyCtrlC, 0, tcell.ModNone},
+		{"Ctrl+C", tcell.KeyCtrlC, 0, tcell.ModCtrl},
+		{"Alt+Rune[f]", tcell.KeyRune, 'f', tcell.ModAlt},
+		{"Shift+Left", tcell.KeyLeft, 0, tcell.ModShift},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ev, err := ParseKey(tc.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ev.Key() != tc.key || ev.Modifiers() != tc.mod ||
+				(tc.key == tcell.KeyRune && ev.Rune() != tc.ch) {
+				t.Errorf("not valid key: %s", ev.Name())
+			}
+		})
+	}
+	for _, name := range []string{"", "Alt+", "Unknown"} {
+		if _, err := ParseKey(name); err == nil {
+			t.Errorf("error is not found for %q", name)
+		}
+	}
+}